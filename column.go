@@ -0,0 +1,432 @@
+package csvx
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// column describes a struct field that is mapped to a csv column through its tags.
+type column struct {
+	index      []int
+	order      []int
+	name       string
+	field      reflect.StructField
+	header     string
+	names      []string
+	no         int
+	hasNo      bool
+	def        string
+	hasDef     bool
+	enum       *enum
+	scale      int
+	scaled     bool
+	split      string
+	pattern    *regexp.Regexp
+	max        int
+	rest       bool
+	required   bool
+	unexported bool
+	text       bool
+	formula    string
+	pos        int
+	width      int
+	align      string
+	pad        rune
+	err        error
+}
+
+var columnCache sync.Map
+
+// columnSet is the columns of a struct type, for csv files and for fixed-width files.
+type columnSet struct {
+	csv   []*column
+	fixed []*column
+}
+
+// columnsOf returns the csv columns declared by the struct type t in field order.
+// Only fields with a `header` tag are returned, its aliases being separated by "|". Anonymous embedded structs are promoted into
+// the same row and struct fields with a `prefix` tag are flattened, their headers being prefixed.
+func columnsOf(t reflect.Type) []*column {
+	return columnSetOf(t).csv
+}
+
+// fixedColumnsOf returns the fields of the struct type t that have a `pos` tag, in field order.
+// A field without a `header` tag is named after the Go field.
+func fixedColumnsOf(t reflect.Type) []*column {
+	return columnSetOf(t).fixed
+}
+
+func columnSetOf(t reflect.Type) *columnSet {
+	if cached, ok := columnCache.Load(t); ok {
+		return cached.(*columnSet)
+	}
+
+	set := &columnSet{}
+	if t.Kind() == reflect.Struct {
+		for _, c := range appendColumns(nil, t, nil, nil, "", "", map[reflect.Type]bool{}) {
+			if _, ok := c.field.Tag.Lookup("header"); ok {
+				set.csv = append(set.csv, c)
+			}
+			if _, ok := c.field.Tag.Lookup("pos"); ok {
+				set.fixed = append(set.fixed, c)
+			}
+		}
+	}
+
+	cached, _ := columnCache.LoadOrStore(t, set)
+	return cached.(*columnSet)
+}
+
+func appendColumns(cols []*column, t reflect.Type, index []int, order []int, name string, prefix string, visiting map[reflect.Type]bool) []*column {
+	visiting[t] = true
+	defer delete(visiting, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		fIndex := append(append([]int{}, index...), i)
+		fOrder := append([]int{}, order...)
+		no, hasNo := tagNo(f)
+		if hasNo {
+			fOrder = append(fOrder, no)
+		}
+
+		// Flatten embedded and prefixed structs
+		tag, ok := f.Tag.Lookup("header")
+		header, flags := parseHeaderTag(tag)
+		fPrefix, pOk := f.Tag.Lookup("prefix")
+		if elem := indirectType(f.Type); elem.Kind() == reflect.Struct && (pOk || (f.Anonymous && !ok)) {
+			// The fields behind an unexported pointer or struct field cannot be set, only an unexported embedded struct is promoted
+			if settable := f.IsExported() || (f.Anonymous && !IsPointer(f.Type)); settable && !visiting[elem] {
+				fName := name + f.Name + "."
+				if f.Anonymous && !pOk {
+					fName = name
+				}
+				cols = appendColumns(cols, elem, fIndex, fOrder, fName, prefix+fPrefix, visiting)
+			}
+			continue
+		}
+
+		pos, posOk := f.Tag.Lookup("pos")
+		if !ok && !posOk {
+			continue
+		}
+		if !ok {
+			// Fixed-width field, named after the Go field
+			header = f.Name
+		}
+		c := &column{
+			index: fIndex,
+			order: fOrder,
+			name:  name + f.Name,
+			field: f,
+			no:    no,
+			hasNo: hasNo,
+			rest:  flags["rest"],
+		}
+		c.unexported = !f.IsExported()
+		c.required = flags["required"]
+		for _, name := range strings.Split(header, "|") {
+			c.names = append(c.names, prefix+name)
+		}
+		c.header = c.names[0]
+		if c.rest && (f.Type.Kind() != reflect.Map || f.Type.Key().Kind() != reflect.String || f.Type.Elem().Kind() != reflect.String) {
+			c.err = fmt.Errorf("rest field %s must be a map[string]string", f.Name)
+		}
+		c.def, c.hasDef = f.Tag.Lookup("default")
+		if scale, sOk := f.Tag.Lookup("scale"); sOk {
+			if n, err := strconv.Atoi(scale); err == nil && n >= 0 {
+				c.scale = n
+				c.scaled = true
+			}
+		}
+		_, c.text = f.Tag.Lookup("text")
+		c.formula = f.Tag.Get("formula")
+		c.split = f.Tag.Get("split")
+		if kind := f.Type.Kind(); c.split == "" && (kind == reflect.Slice || kind == reflect.Array) {
+			// Repeated column group such as `header:"Phone *"`
+			if pattern, rOk := f.Tag.Lookup("pattern"); rOk {
+				c.pattern, c.err = regexp.Compile(pattern)
+			} else if strings.Contains(c.header, "*") {
+				patterns := make([]string, len(c.names))
+				for n, name := range c.names {
+					patterns[n] = strings.Replace(regexp.QuoteMeta(name), `\*`, "(.+)", 1)
+				}
+				c.pattern = regexp.MustCompile("^(?:" + strings.Join(patterns, "|") + ")$")
+			}
+			c.max, _ = strconv.Atoi(f.Tag.Get("max"))
+		}
+		if tag, eOk := f.Tag.Lookup("enum"); eOk {
+			c.enum, c.err = parseEnumTag(c.scalarType(), tag)
+		}
+		if posOk && c.err == nil {
+			c.err = c.parseFixedTags(pos)
+		}
+		cols = append(cols, c)
+	}
+	return cols
+}
+
+// parseFixedTags reads the `pos`, `width`, `align` and `pad` tags of a fixed-width field.
+func (c *column) parseFixedTags(pos string) error {
+	var err error
+	if c.pos, err = strconv.Atoi(pos); err != nil || c.pos < 1 {
+		return fmt.Errorf("invalid pos tag %q on field %s", pos, c.field.Name)
+	}
+	width := c.field.Tag.Get("width")
+	if c.width, err = strconv.Atoi(width); err != nil || c.width < 1 {
+		return fmt.Errorf("invalid width tag %q on field %s", width, c.field.Name)
+	}
+	switch c.align = c.field.Tag.Get("align"); c.align {
+	case "":
+		c.align = "left"
+	case "left", "right":
+	default:
+		return fmt.Errorf("invalid align tag %q on field %s", c.align, c.field.Name)
+	}
+	c.pad = ' '
+	if pad, ok := c.field.Tag.Lookup("pad"); ok {
+		if utf8.RuneCountInString(pad) != 1 {
+			return fmt.Errorf("invalid pad tag %q on field %s", pad, c.field.Name)
+		}
+		c.pad, _ = utf8.DecodeRuneInString(pad)
+	}
+	return nil
+}
+
+// headerFlags are the options accepted after the name in a `header` tag, such as `header:"ID,required"`.
+var headerFlags = map[string]bool{"rest": true, "required": true}
+
+// parseHeaderTag splits a `header` tag into the header name and its trailing options.
+// Commas that are not followed by a known option are kept in the name.
+func parseHeaderTag(tag string) (string, map[string]bool) {
+	flags := map[string]bool{}
+	for {
+		i := strings.LastIndex(tag, ",")
+		if i < 0 || !headerFlags[strings.TrimSpace(tag[i+1:])] {
+			return tag, flags
+		}
+		flags[strings.TrimSpace(tag[i+1:])] = true
+		tag = tag[:i]
+	}
+}
+
+func tagNo(f reflect.StructField) (int, bool) {
+	if no, ok := f.Tag.Lookup("no"); ok {
+		if n, err := strconv.Atoi(no); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// exportColumns returns the columns that have a `no` tag, sorted by their position.
+// The columns of a nested struct with a `no` tag are placed at that position, in the order of their own `no` tags.
+func exportColumns(t reflect.Type) []*column {
+	var cols []*column
+	for _, c := range columnsOf(t) {
+		if c.hasNo && !c.rest {
+			cols = append(cols, c)
+		}
+	}
+	sort.SliceStable(cols, func(i, j int) bool {
+		a, b := cols[i].order, cols[j].order
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return cols
+}
+
+// cell is a column of the exported csv. For a repeated column group, item is the element of the slice written in it.
+type cell struct {
+	col    *column
+	item   int
+	header string
+}
+
+// restCells returns a column for every key of the rest maps in data, sorted by key.
+func restCells(data reflect.Value) []cell {
+	var cells []cell
+	for _, c := range columnsOf(data.Type().Elem()) {
+		if !c.rest || c.err != nil {
+			continue
+		}
+		keys := map[string]bool{}
+		for i := 0; i < data.Len(); i++ {
+			if v, ok := c.valueOf(data.Index(i)); ok {
+				for _, key := range v.MapKeys() {
+					keys[key.String()] = true
+				}
+			}
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+		for _, key := range sorted {
+			cells = append(cells, cell{col: c, item: -1, header: key})
+		}
+	}
+	return cells
+}
+
+// exportCells returns the columns written by Convert for the slice data. A repeated column group is expanded to
+// its `max` tag, the length of an array, or the length of the longest slice in data.
+// The keys of a rest map are written after the declared columns. The mapping renames the columns.
+func exportCells(data reflect.Value, m Mapping) []cell {
+	var cells []cell
+	t := data.Type().Elem()
+	headers := m.headers(columnsOf(t))
+	for _, c := range exportColumns(t) {
+		header := c.header
+		if h, ok := headers[c]; ok && (c.pattern == nil || strings.Contains(h, "*")) {
+			header = h
+		}
+		if c.pattern == nil {
+			cells = append(cells, cell{col: c, item: -1, header: header})
+			continue
+		}
+
+		size := c.max
+		if size == 0 && c.field.Type.Kind() == reflect.Array {
+			size = c.field.Type.Len()
+		}
+		if size == 0 {
+			for i := 0; i < data.Len(); i++ {
+				if v, ok := c.valueOf(data.Index(i)); ok && v.Len() > size {
+					size = v.Len()
+				}
+			}
+		}
+		for i := 0; i < size; i++ {
+			cells = append(cells, cell{col: c, item: i, header: strings.Replace(header, "*", strconv.Itoa(i+1), 1)})
+		}
+	}
+	return append(cells, restCells(data)...)
+}
+
+// cellPositions returns the index of each cell in a headerless file, and the number of columns of the file.
+// The first cell of a top-level field is placed at its `no` tag, `no:"3"` being the third column, so that
+// sparse tags leave empty columns. The other cells of a nested struct or a repeated group follow it.
+func cellPositions(cells []cell) ([]int, int) {
+	positions := make([]int, len(cells))
+	next := 0
+	for i, e := range cells {
+		if order := e.col.order; len(order) > 0 && (i == 0 || len(cells[i-1].col.order) == 0 || cells[i-1].col.order[0] != order[0]) {
+			if order[0]-1 > next {
+				next = order[0] - 1
+			}
+		}
+		positions[i] = next
+		next++
+	}
+	return positions, next
+}
+
+// value returns the text of the cell in the struct value v.
+func (e cell) value(v reflect.Value) string {
+	if e.col.rest {
+		field, ok := e.col.valueOf(v)
+		if !ok || field.IsNil() {
+			return ""
+		}
+		if value := field.MapIndex(reflect.ValueOf(e.header).Convert(field.Type().Key())); value.IsValid() {
+			return value.String()
+		}
+		return ""
+	}
+	if e.item < 0 {
+		return e.col.value(v)
+	}
+	field, ok := e.col.valueOf(v)
+	if !ok || e.item >= field.Len() {
+		return ""
+	}
+	text, _ := e.col.formatItem(field.Index(e.item))
+	return text
+}
+
+// valueOf returns the field of the column in the struct value v.
+// It returns false when a nested struct pointer on the way to the field is nil.
+func (c *column) valueOf(v reflect.Value) (reflect.Value, bool) {
+	for _, i := range c.index {
+		if IsPointer(v.Type()) {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, true
+}
+
+// fieldOf returns the settable field of the column in the struct value v,
+// allocating the nested struct pointers on the way to the field.
+// It returns an error if the field is unexported.
+func (c *column) fieldOf(v reflect.Value) (reflect.Value, error) {
+	if c.unexported {
+		return reflect.Value{}, fmt.Errorf("field %s is unexported", c.name)
+	}
+	for _, i := range c.index {
+		if IsPointer(v.Type()) {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v, nil
+}
+
+// value returns the text of the column in the struct value v, or its `default` tag when the field is nil.
+func (c *column) value(v reflect.Value) string {
+	field, ok := c.valueOf(v)
+	if ok {
+		if text, tOk := c.formatValue(field); tOk {
+			return text
+		}
+	}
+	return c.def
+}
+
+// enumOf returns the enum mapping of the column, either from its `enum` tag or from RegisterEnum.
+func (c *column) enumOf() *enum {
+	if c.enum != nil {
+		return c.enum
+	}
+	return registeredEnum(c.scalarType())
+}
+
+// isList reports whether a field of type t is a slice or an array stored in one cell with the `split` tag.
+func (c *column) isList(t reflect.Type) bool {
+	return c.split != "" && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array)
+}
+
+// scalarType returns the type converted for each value of the column, the element type for lists.
+func (c *column) scalarType() reflect.Type {
+	t := c.field.Type
+	if c.isList(t) {
+		t = t.Elem()
+	}
+	return indirectType(t)
+}
+
+// indirectType returns the element type of t if t is a pointer, otherwise t itself.
+func indirectType(t reflect.Type) reflect.Type {
+	if IsPointer(t) {
+		return t.Elem()
+	}
+	return t
+}
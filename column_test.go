@@ -0,0 +1,95 @@
+package csvx_test
+
+import (
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+type Audit struct {
+	CreatedBy string `header:"Created By" no:"9"`
+}
+
+type Address struct {
+	Street string `header:"Street" no:"1"`
+	City   string `header:"City" no:"2"`
+}
+
+type StructNested struct {
+	Audit
+	ID      int      `header:"ID" no:"1"`
+	Home    Address  `prefix:"Home " no:"2"`
+	Billing *Address `prefix:"Billing " no:"3"`
+}
+
+func TestConvertNested(t *testing.T) {
+	// Given
+	m := []StructNested{
+		{ID: 1, Audit: Audit{CreatedBy: "admin"}, Home: Address{Street: "S1", City: "C1"}},
+		{ID: 2, Billing: &Address{Street: "S2", City: "C2"}},
+	}
+	expected := csvx.Utf8BOM + `"ID","Home Street","Home City","Billing Street","Billing City","Created By"
+"1","S1","C1","","","admin"
+"2","","","S2","C2",""`
+
+	// When
+	result := csvx.Convert(m)
+
+	// Then
+	if result != expected {
+		t.Error("Convert nested error:\n", result)
+	}
+}
+
+func TestParserNested(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"ID", "Home Street", "Home City", "Billing Street", "Billing City", "Created By"},
+		{"1", "S1", "C1", "", "", "admin"},
+		{"2", "", "", "S2", "C2", ""},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructNested](rows)
+
+	// Then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s[0].CreatedBy != "admin" || s[0].Home.City != "C1" || s[0].Billing != nil {
+		t.Error("Parse nested error", s[0])
+	}
+	if s[1].Billing == nil || s[1].Billing.Street != "S2" {
+		t.Error("Parse nested pointer error", s[1])
+	}
+}
+
+type audit struct {
+	UpdatedBy string `header:"Updated By" no:"8"`
+}
+
+type StructUnexportedEmbedded struct {
+	*audit
+	Audit
+	ID int `header:"ID" no:"1"`
+}
+
+func TestParserUnexportedEmbedded(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"ID", "Updated By", "Created By"},
+		{"1", "admin", "root"},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructUnexportedEmbedded](rows)
+	c := csvx.Convert(s)
+
+	// Then
+	if err != nil || s[0].ID != 1 || s[0].audit != nil || s[0].CreatedBy != "root" {
+		t.Fatal("Parse unexported embedded pointer error", s, err)
+	}
+	if c != csvx.Utf8BOM+`"ID","Created By"`+"\n"+`"1","root"` {
+		t.Error("Convert unexported embedded pointer error:\n", c)
+	}
+}
@@ -0,0 +1,142 @@
+package csvx
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+)
+
+// ErrNoCSV is returned when a zip archive contains no csv file.
+var ErrNoCSV = errors.New("zip archive contains no csv file")
+
+// ErrTooLarge is returned when the decompressed content of a file exceeds MaxDecompressedSize.
+var ErrTooLarge = errors.New("decompressed file is too large")
+
+// MaxDecompressedSize is the maximum size in bytes of the content of a gzip file or of a zip entry,
+// which are read in memory by ByteReader, FileHeaderReader and ZipReader, 256 MiB by default.
+// It protects against decompression bombs, small archives expanding to gigabytes.
+var MaxDecompressedSize int64 = 256 << 20
+
+// DecompressReader returns a reader of the decompressed content of r when r starts with the gzip
+// magic bytes, or a reader of r otherwise.
+//
+//	r, err := csvx.DecompressReader(file)
+//	rows := csvx.Reader(csv.NewReader(r))
+func DecompressReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		return gzip.NewReader(br)
+	}
+	return br, nil
+}
+
+// openCompressed returns a reader of the csv in r: the decompressed content of a gzip file,
+// the first csv file of a zip archive, or r itself. Compressed content is read in memory so that
+// a corrupt archive is reported here rather than while reading the records.
+func openCompressed(r io.ReaderAt, size int64) (io.Reader, error) {
+	magic := make([]byte, len(zipMagic))
+	n, _ := r.ReadAt(magic, 0)
+	magic = magic[:n]
+
+	switch {
+	case bytes.HasPrefix(magic, zipMagic):
+		zr, err := zip.NewReader(r, size)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range zr.File {
+			if isCSVFile(f) {
+				return readZipFile(f)
+			}
+		}
+		return nil, ErrNoCSV
+	case bytes.HasPrefix(magic, gzipMagic):
+		gr, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, err
+		}
+		return readDecompressed(gr)
+	}
+	return io.NewSectionReader(r, 0, size), nil
+}
+
+func readZipFile(f *zip.File) (io.Reader, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return readDecompressed(rc)
+}
+
+// readDecompressed reads r in memory, returning ErrTooLarge past MaxDecompressedSize.
+func readDecompressed(r io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxDecompressedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > MaxDecompressedSize {
+		return nil, ErrTooLarge
+	}
+	return bytes.NewReader(data), nil
+}
+
+// isCSVFile reports whether the zip entry is a csv file, skipping the metadata of macOS archives.
+func isCSVFile(f *zip.File) bool {
+	name := f.Name
+	return !f.FileInfo().IsDir() && !strings.HasPrefix(name, "__MACOSX/") && !strings.HasPrefix(path.Base(name), "._") &&
+		strings.EqualFold(path.Ext(name), ".csv")
+}
+
+// ZipReader reads every csv file of the zip archive r of the given size, in the order of the archive,
+// and calls onFile with its name and rows. It stops at the first error returned by onFile.
+// Files are read like ByteReader, configured by options.
+//
+//	err := csvx.ZipReader(bytes.NewReader(data), int64(len(data)), func(name string, rows [][]string) error {
+//		orders := csvx.Parser[Order](rows)
+//		return nil
+//	})
+func ZipReader(r io.ReaderAt, size int64, onFile func(name string, rows [][]string) error, options ...func(r *csv.Reader)) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		if !isCSVFile(f) {
+			continue
+		}
+		content, err := readZipFile(f)
+		if err != nil {
+			return err
+		}
+		rows := Reader(csv.NewReader(TranscodeReader(content, UTF8)), options...)
+		if err := onFile(f.Name, rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gzipText compresses the text.
+func gzipText(text string) (string, error) {
+	var buffer bytes.Buffer
+	w := gzip.NewWriter(&buffer)
+	if _, err := w.Write([]byte(text)); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
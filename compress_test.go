@@ -0,0 +1,157 @@
+package csvx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+func gzipBytes(text string) []byte {
+	var buffer bytes.Buffer
+	w := gzip.NewWriter(&buffer)
+	_, _ = w.Write([]byte(text))
+	_ = w.Close()
+	return buffer.Bytes()
+}
+
+func zipBytes(files ...string) []byte {
+	var buffer bytes.Buffer
+	w := zip.NewWriter(&buffer)
+	for i := 0; i < len(files); i += 2 {
+		f, _ := w.Create(files[i])
+		_, _ = f.Write([]byte(files[i+1]))
+	}
+	_ = w.Close()
+	return buffer.Bytes()
+}
+
+func TestByteReaderGzip(t *testing.T) {
+	// Given
+	data := gzipBytes(csvx.Utf8BOM + "ID,Name\n1,N1\n")
+
+	// When
+	rows := csvx.ByteReader(data)
+
+	// Then
+	if !reflect.DeepEqual(rows, [][]string{{"ID", "Name"}, {"1", "N1"}}) {
+		t.Error("ByteReader error", rows)
+	}
+}
+
+func TestByteReaderWithEncodingGzip(t *testing.T) {
+	// Given
+	data := gzipBytes(string([]byte{'I', 'D', ',', 'N', '\n', '1', ',', 0xCA, 0xC7, 0xD1, 0xCA, 0xB4, 0xD5, '\n'}))
+
+	// When
+	rows := csvx.ByteReaderWithEncoding(data, csvx.Windows874)
+
+	// Then
+	if !reflect.DeepEqual(rows, [][]string{{"ID", "N"}, {"1", "สวัสดี"}}) {
+		t.Error("ByteReaderWithEncoding error", rows)
+	}
+}
+
+func TestByteReaderZip(t *testing.T) {
+	// Given
+	data := zipBytes("readme.txt", "hello", "orders.csv", "ID\n1\n", "refunds.csv", "ID\n2\n")
+
+	// When
+	rows := csvx.ByteReader(data)
+
+	// Then
+	if !reflect.DeepEqual(rows, [][]string{{"ID"}, {"1"}}) {
+		t.Error("ByteReader error", rows)
+	}
+}
+
+func TestZipReader(t *testing.T) {
+	// Given
+	data := zipBytes("orders.csv", "ID\n1\n", "__MACOSX/._orders.csv", "x", "reports/refunds.CSV", "ID\n2\n")
+
+	// When
+	files := map[string][][]string{}
+	err := csvx.ZipReader(bytes.NewReader(data), int64(len(data)), func(name string, rows [][]string) error {
+		files[name] = rows
+		return nil
+	})
+
+	// Then
+	expected := map[string][][]string{"orders.csv": {{"ID"}, {"1"}}, "reports/refunds.CSV": {{"ID"}, {"2"}}}
+	if err != nil || !reflect.DeepEqual(files, expected) {
+		t.Error("ZipReader error", files, err)
+	}
+}
+
+func TestDecompressReader(t *testing.T) {
+	// Given
+	data := gzipBytes("ID\n1\n")
+
+	// When
+	r, err := csvx.DecompressReader(bytes.NewReader(data))
+	plain, _ := io.ReadAll(r)
+
+	// Then
+	if err != nil || string(plain) != "ID\n1\n" {
+		t.Error("DecompressReader error", string(plain), err)
+	}
+}
+
+func TestConvertWithOptionsGzip(t *testing.T) {
+	// Given
+	m := []MyStruct{{ID: 1, Name: "N1"}}
+
+	// When
+	result, err := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+		o.Gzip = true
+	})
+	rows := csvx.ByteReader([]byte(result))
+
+	// Then
+	if err != nil || !reflect.DeepEqual(rows, [][]string{{"ID", "Name Space"}, {"1", "N1"}}) {
+		t.Error("Convert error", rows, err)
+	}
+}
+
+func TestZipWithoutCSV(t *testing.T) {
+	// Given
+	data := zipBytes("readme.txt", "hello")
+
+	// When
+	rows := csvx.ByteReader(data)
+	err := csvx.ZipReader(bytes.NewReader(data), int64(len(data)), func(name string, rows [][]string) error {
+		return errors.New("unexpected file " + name)
+	})
+
+	// Then
+	if len(rows) != 0 || err != nil {
+		t.Error("ByteReader error", rows, err)
+	}
+}
+
+func TestMaxDecompressedSize(t *testing.T) {
+	// Given
+	text := "ID,Name\n" + strings.Repeat("1,N1\n", 1000)
+	gz := gzipBytes(text)
+	zipped := zipBytes("orders.csv", text)
+	defer func(size int64) { csvx.MaxDecompressedSize = size }(csvx.MaxDecompressedSize)
+	csvx.MaxDecompressedSize = int64(len(text)) - 1
+
+	// When
+	gzRows := csvx.ByteReader(gz)
+	zipRows := csvx.ByteReader(zipped)
+	err := csvx.ZipReader(bytes.NewReader(zipped), int64(len(zipped)), func(name string, rows [][]string) error {
+		return nil
+	})
+
+	// Then
+	if len(gzRows) != 0 || len(zipRows) != 0 || !errors.Is(err, csvx.ErrTooLarge) {
+		t.Error("Decompressed size must be limited", len(gzRows), len(zipRows), err)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"encoding/csv"
 	"fmt"
 	"reflect"
-	"strconv"
 	"strings"
 )
 
@@ -37,88 +36,196 @@ func Format(cell []string) string {
 //	"1","N1"
 //	"2","N2"
 func Convert[T any](data []T, ignoreDoubleQuote ...bool) string {
-	size := len(data)
-	if size > 0 {
+	csv, _ := convert(data, &ConvertOptions{IgnoreDoubleQuote: len(ignoreDoubleQuote) > 0, AllowFormulas: true})
+	return csv
+}
+
+// ConvertOptions configures how ConvertWithOptions writes the csv.
+type ConvertOptions struct {
+	// IgnoreDoubleQuote writes the cells without double quotes, like Convert(data, true).
+	IgnoreDoubleQuote bool
+
+	// Mapping renames the columns at runtime, from the header to write to the struct field, overriding the `header` tags.
+	Mapping Mapping
+
+	// Headerless omits the header row, for positional files parsed with ParserOptions.Headerless.
+	Headerless bool
+
+	// Comma is the field delimiter, ',' when zero.
+	Comma rune
+
+	// Delimiter separates the fields when it is longer than one character, such as "||" or "~|~", overriding Comma.
+	Delimiter string
+
+	// Encoding is the output encoding, UTF-8 by default. UTF-8 and UTF-16 are written with a byte order mark.
+	Encoding Encoding
+
+	// OmitBOM writes UTF-8 and UTF-16 without the byte order mark.
+	OmitBOM bool
+
+	// LineTerminator ends the rows, "\n" when empty, such as "\r\n" or the ASCII record separator "\x1e".
+	// The last row of ConvertWithOptions is not terminated.
+	LineTerminator string
+
+	// SepLine writes an Excel "sep=," line before the header, so that Excel splits the columns with Comma.
+	SepLine bool
+
+	// ProtectText writes the numbers that Excel would alter as text formulas such as ="00123":
+	// numbers with leading zeros and numbers of more than 11 digits. The `text` tag protects every value of a column.
+	ProtectText bool
+
+	// Gzip compresses the output, after the encoding.
+	Gzip bool
+
+	// AllowFormulas writes the cells verbatim. By default the cells that start with '=', '+', '-', '@', a tab or
+	// a carriage return are prefixed with a single quote, so that spreadsheets do not evaluate them as formulas.
+	// Numbers such as -12.5 are kept in the numeric fields, in the columns with the `formula:"number"` tag and in
+	// the rows of ManualConvertWithOptions, and the columns with the `formula:"allow"` tag are written verbatim.
+	AllowFormulas bool
+}
+
+func convertOptions(options []func(o *ConvertOptions)) *ConvertOptions {
+	o := &ConvertOptions{}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+func (o *ConvertOptions) comma() string {
+	if o.Delimiter != "" {
+		return o.Delimiter
+	}
+	if o.Comma == 0 {
+		return ","
+	}
+	return string(o.Comma)
+}
+
+func (o *ConvertOptions) lineTerminator() string {
+	if o.LineTerminator == "" {
+		return "\n"
+	}
+	return o.LineTerminator
+}
+
+// sepLine returns the Excel "sep=" line, or an empty string.
+func (o *ConvertOptions) sepLine() string {
+	if !o.SepLine {
+		return ""
+	}
+	return "sep=" + o.comma() + o.lineTerminator()
+}
 
-		// Config format value
-		valueFormatCore := "%v"
-		valueFormat := "\"%v\""
-		if len(ignoreDoubleQuote) > 0 {
-			valueFormat = valueFormatCore
+// sanitizeRow returns a copy of the row of ManualConvertWithOptions with the formulas sanitized and the text protected.
+func (o *ConvertOptions) sanitizeRow(row []string) []string {
+	sanitized := make([]string, len(row))
+	for c, value := range row {
+		sanitized[c] = o.sanitize(value, nil)
+		if o.protects(sanitized[c], false) {
+			sanitized[c] = excelText(sanitized[c])
 		}
+	}
+	return sanitized
+}
 
-		// Initialize the element
-		var headers []string
-		rows := make([][]string, size)
-
-		// Mapping
-		sheets := []string{}
-		for r, d := range data {
-			el := reflect.ValueOf(&d).Elem()
-
-			colsRaw := el.NumField()
-			cols := 0
-			for c := 0; c < colsRaw; c++ {
-				_, fOk := headerLookup[T](d, c)
-				_, iOk := noLookup[T](d, c)
-				if !fOk || !iOk {
-					continue
-				}
-				cols++
-			}
-			if headers == nil {
-				headers = make([]string, cols)
-			}
-			if len(rows[r]) == 0 {
-				rows[r] = make([]string, cols)
-			}
+// protects reports whether the value is written as an Excel text formula, see ProtectText.
+func (o *ConvertOptions) protects(value string, text bool) bool {
+	return (text && value != "") || (o.ProtectText && excelAltered(value))
+}
 
-			for c := 0; c < colsRaw; c++ {
-				value := el.Field(c)
-				field, fOk := headerLookup[T](d, c)
-				index, iOk := noLookup[T](d, c)
-				if !fOk || !iOk {
-					continue
-				}
+// encode transcodes the csv text to the output encoding, after the byte order mark, and compresses it with Gzip.
+func (o *ConvertOptions) encode(text string) (string, error) {
+	data, err := Encode(text, o.Encoding)
+	if err != nil {
+		return "", err
+	}
+	text = string(data)
+	if !o.OmitBOM {
+		text = string(o.Encoding.BOM()) + text
+	}
+	if o.Gzip {
+		return gzipText(text)
+	}
+	return text, nil
+}
 
-				if i, err := strconv.Atoi(index); err == nil {
-					if r == 0 {
-						headers[i-1] = fmt.Sprintf(valueFormat, field)
-					}
-					if IsFloat(value.Type()) {
-						rows[r][i-1] = fmt.Sprintf(valueFormat, F64ToString(value.Float()))
-					} else {
-						nValue := ""
-						if IsPointer(value.Type()) {
-							if value.Elem().IsValid() {
-								nValue = RemoveDoubleQuote(fmt.Sprintf(valueFormatCore, value.Elem()))
-							} else {
-								// Use default from tag
-								if def, dOk := defaultLookup[T](d, c); dOk {
-									nValue = def
-								}
-							}
-						} else {
-							nValue = RemoveDoubleQuote(fmt.Sprintf(valueFormatCore, value))
-						}
-						rows[r][i-1] = fmt.Sprintf(valueFormat, nValue)
-					}
-				}
-			}
+// ConvertWithOptions converts array struct to csv format like Convert, configured by options.
+// Unlike Convert, the cells that spreadsheets would evaluate as formulas are sanitized, see ConvertOptions.AllowFormulas.
+// It returns an error if a field of the mapping does not exist, or an *EncodeError if a character
+// cannot be written in the output encoding.
+//
+//	csv, err := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+//		o.Mapping = csvx.Mapping{"Customer": "Name"}
+//	})
+func ConvertWithOptions[T any](data []T, options ...func(o *ConvertOptions)) (string, error) {
+	o := convertOptions(options)
+	if err := o.Mapping.validate(columnsOf(reflect.TypeOf(data).Elem())); err != nil {
+		return "", err
+	}
+	return convert(data, o)
+}
 
-			// Convert array to csv format
-			if len(sheets) == 0 {
-				sheets = append(sheets, Format(headers))
+func convert[T any](data []T, o *ConvertOptions) (string, error) {
+	if len(data) > 0 {
+		return convertRows(data, o)
+	}
+
+	return "", nil
+}
+
+// convertRows converts the structs like convert, the header row being written even when data is empty.
+func convertRows[T any](data []T, o *ConvertOptions) (string, error) {
+	// Config format value
+	valueFormat := "\"%v\""
+	if o.IgnoreDoubleQuote {
+		valueFormat = "%v"
+	}
+
+	// Initialize the element
+	cols := exportCells(reflect.ValueOf(data), o.Mapping)
+	headers := make([]string, len(cols))
+	for c, col := range cols {
+		headers[c] = fmt.Sprintf(valueFormat, o.sanitize(col.header, nil))
+	}
+
+	// Mapping
+	sheets := []string{strings.Join(headers, o.comma())}
+	if o.Headerless {
+		sheets = sheets[:0]
+	}
+	// Positional files leave empty columns for the gaps in the `no` tags
+	positions, width := make([]int, len(cols)), len(cols)
+	for c := range positions {
+		positions[c] = c
+	}
+	if o.Headerless {
+		positions, width = cellPositions(cols)
+	}
+
+	for _, d := range data {
+		el := reflect.ValueOf(&d).Elem()
+		row := make([]string, width)
+		for c := range row {
+			row[c] = fmt.Sprintf(valueFormat, "")
+		}
+		for c, col := range cols {
+			value := o.sanitize(RemoveDoubleQuote(col.value(el)), col.col)
+			if o.protects(value, col.col.text) {
+				value = excelText(value)
+				if !o.IgnoreDoubleQuote {
+					value = strings.ReplaceAll(value, `"`, `""`)
+				}
 			}
-			sheets = append(sheets, Format(rows[r]))
+			row[positions[c]] = fmt.Sprintf(valueFormat, value)
 		}
 
-		// Add enter end line
-		result := strings.Join(sheets, "\n")
-		return Utf8BOM + result
+		// Convert array to csv format
+		sheets = append(sheets, strings.Join(row, o.comma()))
 	}
 
-	return ""
+	// Add enter end line
+	return o.encode(o.sepLine() + strings.Join(sheets, o.lineTerminator()))
 }
 
 // ManualConvert performs a manual conversion of the input data.
@@ -141,6 +248,37 @@ func ManualConvert[T any](data []T, headers []string, onRecord func(data T) []st
 	return fmt.Sprintf("%s%s", Utf8BOM, buffer.String())
 }
 
+// ManualConvertWithOptions performs a manual conversion of the input data like ManualConvert, written with
+// the Headerless, Comma, Delimiter, Encoding, OmitBOM, LineTerminator, SepLine, ProtectText, Gzip and AllowFormulas options.
+// It returns an *EncodeError if a character cannot be written in the output encoding.
+//
+//	csv, err := csvx.ManualConvertWithOptions(m, headers, onRecord, func(o *csvx.ConvertOptions) {
+//		o.Encoding = csvx.Windows874
+//	})
+func ManualConvertWithOptions[T any](data []T, headers []string, onRecord func(data T) []string, options ...func(o *ConvertOptions)) (string, error) {
+	if len(data) == 0 {
+		return "", nil
+	}
+	o := convertOptions(options)
+
+	var buffer bytes.Buffer
+	w := newRecordWriter(&buffer, o)
+	if !o.Headerless {
+		if err := w.Write(headers); err != nil {
+			return "", err
+		}
+	}
+	for _, d := range data {
+		if err := w.Write(onRecord(d)); err != nil {
+			return "", err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
 // TryConvert attempts to convert the input data to the specified format.
 // It handles errors gracefully and returns the converted result along with an error (if any).
 func TryConvert[T any](data []T, ignoreDoubleQuote ...bool) string {
@@ -149,75 +287,30 @@ func TryConvert[T any](data []T, ignoreDoubleQuote ...bool) string {
 	}
 
 	// Config format value
-	valueFormatCore := "%v"
 	valueFormat := "\"%v\""
 	if len(ignoreDoubleQuote) > 0 {
-		valueFormat = valueFormatCore
-	}
-
-	// Use reflection to get the type of the struct
-	t := reflect.TypeOf(data[0])
-
-	cols := 0
-	numField := t.NumField()
-	hmap := make(map[int]string)
-	nmap := make(map[int]int)
-	dmap := make(map[int]string)
-	for i := 0; i < numField; i++ {
-		header, hOk := headerLookup[T](data[0], i)
-		noStr, nOk := noLookup[T](data[0], i)
-		def, _ := defaultLookup[T](data[0], i)
-		if hOk && nOk {
-			no, err := strconv.Atoi(noStr)
-			if err != nil {
-				continue
-			}
-			// Convert to index of array
-			index := no - 1
-			nmap[index] = i
-			hmap[index] = header
-			dmap[index] = def
-			cols++
-		}
+		valueFormat = "%v"
 	}
 
+	cols := exportCells(reflect.ValueOf(data), nil)
+
 	var headers strings.Builder
 	var records strings.Builder
 
 	for r, d := range data {
 		el := reflect.ValueOf(&d).Elem()
-		for c := 0; c < cols; c++ {
-			idx := nmap[c]
-
+		for c, col := range cols {
 			// Header
-			header := hmap[c]
 			if r == 0 {
-				headers.WriteString(fmt.Sprintf("%v", header))
-				if c < cols-1 {
+				headers.WriteString(fmt.Sprintf("%v", col.header))
+				if c < len(cols)-1 {
 					headers.WriteString(",")
 				}
 			}
 
 			// Records
-			field := el.Field(idx)
-
-			if IsFloat(field.Type()) {
-				records.WriteString(fmt.Sprintf(valueFormat, F64ToString(field.Float())))
-			} else {
-				value := ""
-				if IsPointer(field.Type()) {
-					if field.Elem().IsValid() {
-						value = fmt.Sprintf(valueFormatCore, field.Elem())
-					} else {
-						// Use default from tag
-						value = dmap[c]
-					}
-				} else {
-					value = fmt.Sprintf(valueFormatCore, field)
-				}
-				records.WriteString(fmt.Sprintf(valueFormat, value))
-			}
-			if c < cols-1 {
+			records.WriteString(fmt.Sprintf(valueFormat, col.value(el)))
+			if c < len(cols)-1 {
 				records.WriteString(",")
 			} else {
 				records.WriteString("\n")
@@ -227,15 +320,3 @@ func TryConvert[T any](data []T, ignoreDoubleQuote ...bool) string {
 
 	return fmt.Sprintf("%s%s\n%s", Utf8BOM, headers.String(), records.String())
 }
-
-func headerLookup[T any](d T, c int) (string, bool) {
-	return reflect.ValueOf(d).Type().Field(c).Tag.Lookup("header")
-}
-
-func noLookup[T any](d T, c int) (string, bool) {
-	return reflect.ValueOf(d).Type().Field(c).Tag.Lookup("no")
-}
-
-func defaultLookup[T any](d T, c int) (string, bool) {
-	return reflect.ValueOf(d).Type().Field(c).Tag.Lookup("default")
-}
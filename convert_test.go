@@ -1,6 +1,7 @@
 package csvx_test
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -144,3 +145,68 @@ func BenchmarkTryConvert(b *testing.B) {
 		}
 	}
 }
+
+func TestConvertWithOptionsEncoding(t *testing.T) {
+	// Given
+	m := []MyStruct{{ID: 1, Name: "สมชาย"}}
+
+	// When
+	result, err := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+		o.IgnoreDoubleQuote = true
+		o.Comma = ';'
+		o.Encoding = csvx.Windows874
+	})
+
+	// Then
+	if err != nil || result != "ID;Name Space\n1;\xca\xc1\xaa\xd2\xc2" {
+		t.Errorf("Convert error: %q %v", result, err)
+	}
+}
+
+func TestConvertWithOptionsUTF16(t *testing.T) {
+	// Given
+	m := []MyStruct{{ID: 1, Name: "N1"}}
+
+	// When
+	result, err := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+		o.Encoding = csvx.UTF16LE
+	})
+
+	// Then
+	if err != nil || result[:2] != "\xff\xfe" || string(csvx.Decode([]byte(result), csvx.UTF8)) != "\"ID\",\"Name Space\"\n\"1\",\"N1\"" {
+		t.Errorf("Convert error: %q %v", result, err)
+	}
+}
+
+func TestConvertWithOptionsOmitBOM(t *testing.T) {
+	// Given
+	m := []MyStruct{{ID: 1, Name: "N1"}}
+
+	// When
+	result, _ := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+		o.OmitBOM = true
+	})
+
+	// Then
+	if result != "\"ID\",\"Name Space\"\n\"1\",\"N1\"" {
+		t.Errorf("Convert error: %q", result)
+	}
+}
+
+func TestManualConvertWithOptionsUnencodable(t *testing.T) {
+	// Given
+	m := []MyStruct{{ID: 1, Name: "東京"}}
+
+	// When
+	_, err := csvx.ManualConvertWithOptions(m, []string{"ID", "Name"}, func(data MyStruct) []string {
+		return []string{fmt.Sprint(data.ID), data.Name}
+	}, func(o *csvx.ConvertOptions) {
+		o.Encoding = csvx.Windows874
+	})
+
+	// Then
+	var encodeErr *csvx.EncodeError
+	if !errors.As(err, &encodeErr) || encodeErr.Rune != '東' {
+		t.Error("ManualConvert error", err)
+	}
+}
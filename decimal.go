@@ -0,0 +1,168 @@
+package csvx
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// Decimal is an exact decimal number kept as its text, for money columns that must not go through float64.
+//
+//	type Invoice struct {
+//		Amount csvx.Decimal `header:"Amount" no:"1" scale:"2"`
+//	}
+type Decimal string
+
+// ParseDecimal returns the Decimal of text, or an error if text is not a decimal number such as "-1234.50".
+func ParseDecimal(text string) (Decimal, error) {
+	text = strings.TrimSpace(text)
+	if !isDecimal(text) {
+		return "", fmt.Errorf("invalid decimal %q", text)
+	}
+	return Decimal(text), nil
+}
+
+// Rat returns the exact value of the decimal, or nil if it is not a valid decimal number.
+func (d Decimal) Rat() *big.Rat {
+	r, ok := new(big.Rat).SetString(string(d))
+	if !ok {
+		return nil
+	}
+	return r
+}
+
+// Round returns the decimal rounded to scale decimal places, with halves rounded away from zero.
+func (d Decimal) Round(scale int) Decimal {
+	return Decimal(roundDecimal(string(d), scale))
+}
+
+// String returns the text of the decimal.
+func (d Decimal) String() string {
+	return string(d)
+}
+
+var (
+	decimalType  = reflect.TypeOf(Decimal(""))
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+)
+
+// isDecimal reports whether text is an optionally signed number with an optional fraction.
+func isDecimal(text string) bool {
+	if text != "" && (text[0] == '-' || text[0] == '+') {
+		text = text[1:]
+	}
+	digits := 0
+	dot := false
+	for _, r := range text {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '.' && !dot:
+			dot = true
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// parseDecimal sets v when its type is Decimal or one of the math/big numbers.
+// It returns false if v is not one of these types.
+func parseDecimal(v reflect.Value, text string) (bool, error) {
+	text = strings.TrimSpace(text)
+	switch v.Type() {
+	case decimalType:
+		d, err := ParseDecimal(text)
+		if err != nil {
+			return true, err
+		}
+		v.SetString(string(d))
+	case bigIntType:
+		if _, ok := v.Addr().Interface().(*big.Int).SetString(text, 10); !ok {
+			return true, fmt.Errorf("invalid integer %q", text)
+		}
+	case bigRatType:
+		if _, ok := v.Addr().Interface().(*big.Rat).SetString(text); !ok {
+			return true, fmt.Errorf("invalid decimal %q", text)
+		}
+	case bigFloatType:
+		// Keep enough precision for every digit of the text
+		f, _, err := big.ParseFloat(text, 10, uint(len(text))*4+64, big.ToNearestEven)
+		if err != nil {
+			return true, err
+		}
+		v.Set(reflect.ValueOf(*f))
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// formatDecimal returns the exact text of v when its type is Decimal or one of the math/big numbers.
+// The math/big numbers of unexported fields, which cannot be read with Interface, are not formatted.
+func formatDecimal(v reflect.Value) (string, bool) {
+	if !v.CanInterface() && v.Type() != decimalType {
+		return "", false
+	}
+	switch v.Type() {
+	case decimalType:
+		return v.String(), true
+	case bigIntType:
+		return addressable(v).Interface().(*big.Int).String(), true
+	case bigRatType:
+		return ratString(addressable(v).Interface().(*big.Rat)), true
+	case bigFloatType:
+		return addressable(v).Interface().(*big.Float).Text('f', -1), true
+	}
+	return "", false
+}
+
+// addressable returns a pointer to v, copying v when it is not addressable.
+func addressable(v reflect.Value) reflect.Value {
+	if v.CanAddr() {
+		return v.Addr()
+	}
+	p := reflect.New(v.Type())
+	p.Elem().Set(v)
+	return p
+}
+
+// ratString returns r as an exact decimal, or as a fraction when its decimal expansion does not terminate.
+func ratString(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+
+	// A fraction terminates when its denominator only has the factors 2 and 5
+	denom := new(big.Int).Set(r.Denom())
+	five := big.NewInt(5)
+	twos, fives := 0, 0
+	for denom.Bit(0) == 0 {
+		denom.Rsh(denom, 1)
+		twos++
+	}
+	for new(big.Int).Mod(denom, five).Sign() == 0 {
+		denom.Quo(denom, five)
+		fives++
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return r.RatString()
+	}
+	if twos > fives {
+		return r.FloatString(twos)
+	}
+	return r.FloatString(fives)
+}
+
+// roundDecimal rounds the number in text to scale decimal places, with halves rounded away from zero.
+// The text is returned unchanged if it is not a number.
+func roundDecimal(text string, scale int) string {
+	r, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return text
+	}
+	return r.FloatString(scale)
+}
@@ -0,0 +1,72 @@
+package csvx_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+type StructMoney struct {
+	Amount csvx.Decimal `header:"Amount" no:"1" scale:"2"`
+	Total  *big.Rat     `header:"Total" no:"2"`
+	Count  *big.Int     `header:"Count" no:"3"`
+	Rate   *big.Float   `header:"Rate" no:"4"`
+	Fee    float64      `header:"Fee" no:"5" scale:"2"`
+}
+
+func TestParserDecimal(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"Amount", "Total", "Count", "Rate", "Fee"},
+		{"12345678901234.565", "0.3", "123456789012345678901234567890", "0.1", "2.675"},
+		{"", "", "", "", ""},
+	}
+	expected := csvx.Utf8BOM + `"Amount","Total","Count","Rate","Fee"
+"12345678901234.57","0.3","123456789012345678901234567890","0.1","2.68"
+"","","","","0.00"`
+
+	// When
+	s, err := csvx.TryParser[StructMoney](rows)
+	c := csvx.Convert(s)
+
+	// Then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s[0].Total.Cmp(new(big.Rat).Add(big.NewRat(1, 10), big.NewRat(2, 10))) != 0 {
+		t.Error("Parse big.Rat error", s[0].Total)
+	}
+	if c != expected {
+		t.Error("Convert decimal error:\n", c)
+	}
+}
+
+type StructUnexportedMoney struct {
+	ID     int      `header:"ID" no:"1"`
+	amount *big.Int `header:"Amount" no:"2"`
+}
+
+func TestConvertUnexportedDecimal(t *testing.T) {
+	// Given
+	m := []StructUnexportedMoney{{ID: 1, amount: big.NewInt(5)}}
+
+	// When
+	result := csvx.Convert(m)
+
+	// Then
+	if !strings.HasPrefix(result, csvx.Utf8BOM+`"ID","Amount"`+"\n"+`"1",`) {
+		t.Error("Convert unexported decimal error:\n", result)
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	if _, err := csvx.ParseDecimal("1,000.00"); err == nil {
+		t.Error("Invalid decimal must be an error")
+	}
+	d, err := csvx.ParseDecimal("-0.125")
+	if err != nil || d.Round(2) != "-0.13" {
+		t.Error("Round decimal error", d.Round(2))
+	}
+}
@@ -0,0 +1,267 @@
+package csvx
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Dialect describes the format of a csv file, as inferred by Sniff.
+type Dialect struct {
+	// Comma is the field delimiter: ',', ';', '\t' or '|'.
+	Comma rune
+	// Quote reports whether fields are enclosed in double quotes.
+	Quote bool
+	// LineTerminator is "\n", "\r\n" or "\r".
+	LineTerminator string
+	// BOM reports whether the file starts with a byte order mark.
+	BOM bool
+	// Header reports whether the first row looks like a header row.
+	Header bool
+	// SepLine reports whether the file starts with an Excel "sep=;" line.
+	SepLine bool
+	// ProtectText writes the numbers that Excel would alter, such as "00123", as text formulas.
+	// It is only used on export and is never inferred by Sniff.
+	ProtectText bool
+}
+
+// Reader configures a csv.Reader for the dialect, as an option of Reader.
+//
+//	rows := csvx.Reader(csv.NewReader(r), dialect.Reader)
+func (d Dialect) Reader(r *csv.Reader) {
+	if d.Comma != 0 {
+		r.Comma = d.Comma
+	}
+}
+
+// Parser configures Parser for the dialect: the "sep=" line is skipped and a file without
+// a header row is parsed with ParserOptions.Headerless.
+//
+//	s := csvx.Parser[Struct](rows, dialect.Parser)
+func (d Dialect) Parser(o *ParserOptions) {
+	o.Headerless = !d.Header
+	if d.SepLine {
+		o.HeaderRow = 1
+	}
+}
+
+// Convert configures ConvertWithOptions for the dialect, as an option of ConvertWithOptions.
+//
+//	csv, err := csvx.ConvertWithOptions(m, dialect.Convert)
+func (d Dialect) Convert(o *ConvertOptions) {
+	o.Comma = d.Comma
+	o.IgnoreDoubleQuote = !d.Quote
+	o.LineTerminator = d.LineTerminator
+	o.OmitBOM = !d.BOM
+	o.Headerless = !d.Header
+	o.SepLine = d.SepLine
+	o.ProtectText = d.ProtectText
+}
+
+// sniffSize is the size of the sample read by Sniff.
+const sniffSize = 64 * 1024
+
+// sniffRows is the number of rows used to infer the delimiter and the header.
+const sniffRows = 20
+
+// Sniff reads a sample of r and infers its Dialect. When r is an io.Seeker it is rewound to
+// where it was, otherwise the sample is consumed and SniffReader should be used instead.
+func Sniff(r io.Reader) (Dialect, error) {
+	var offset int64
+	seeker, seekable := r.(io.Seeker)
+	if seekable {
+		var err error
+		if offset, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			return Dialect{}, err
+		}
+	}
+
+	sample, err := readSample(r)
+	if err != nil {
+		return Dialect{}, err
+	}
+	if seekable {
+		if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+			return Dialect{}, err
+		}
+	}
+	return sniff(sample), nil
+}
+
+// SniffReader reads a sample of r and infers its Dialect. It returns a reader that replays the
+// sample without the BOM, followed by the rest of r.
+//
+//	dialect, r, err := csvx.SniffReader(file)
+//	rows := csvx.Reader(csv.NewReader(r), dialect.Reader)
+//	s := csvx.Parser[Struct](rows, dialect.Parser)
+func SniffReader(r io.Reader) (Dialect, io.Reader, error) {
+	sample, err := readSample(r)
+	if err != nil {
+		return Dialect{}, nil, err
+	}
+	return sniff(sample), io.MultiReader(bytes.NewReader(bytes.TrimPrefix(sample, []byte(Utf8BOM))), r), nil
+}
+
+func readSample(r io.Reader) ([]byte, error) {
+	sample := make([]byte, sniffSize)
+	n, err := io.ReadFull(r, sample)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	return sample[:n], nil
+}
+
+func sniff(sample []byte) Dialect {
+	d := Dialect{Comma: ',', LineTerminator: "\n"}
+
+	text := string(sample)
+	if strings.HasPrefix(text, Utf8BOM) {
+		d.BOM = true
+		text = text[len(Utf8BOM):]
+	}
+	if i := strings.IndexAny(text, "\r\n"); i >= 0 {
+		if strings.HasPrefix(text[i:], "\r\n") {
+			d.LineTerminator = "\r\n"
+		} else {
+			d.LineTerminator = text[i : i+1]
+		}
+	}
+	if d.LineTerminator == "\r" {
+		text = strings.ReplaceAll(text, "\r", "\n")
+	}
+
+	// Drop the last line when the sample ends inside it
+	if len(sample) == sniffSize {
+		if i := strings.LastIndex(text, "\n"); i > 0 {
+			text = text[:i+1]
+		}
+	}
+
+	// Excel "sep=;" line
+	firstLine := text
+	if i := strings.Index(text, "\n"); i >= 0 {
+		firstLine = text[:i]
+	}
+	if sep := strings.TrimSpace(firstLine); len(sep) > 4 && strings.EqualFold(sep[:4], "sep=") {
+		if r, size := utf8.DecodeRuneInString(sep[4:]); size == len(sep)-4 {
+			d.SepLine = true
+			d.Comma = r
+			text = strings.TrimPrefix(text[len(firstLine):], "\n")
+		}
+	}
+
+	var rows [][]string
+	if d.SepLine {
+		rows = sniffRecords(text, d.Comma)
+	} else {
+		d.Comma, rows = sniffComma(text)
+	}
+	d.Quote = strings.Contains(text, `"`)
+	d.Header = sniffHeader(rows)
+	return d
+}
+
+// sniffComma returns the candidate delimiter that splits the rows of text into the most consistent number
+// of fields, preferring more fields, and the rows split by it.
+func sniffComma(text string) (rune, [][]string) {
+	comma, rows := ',', sniffRecords(text, ',')
+	bestRatio, bestFields := 0.0, 1
+	for _, candidate := range []rune{',', ';', '\t', '|'} {
+		records := sniffRecords(text, candidate)
+		if len(records) == 0 {
+			continue
+		}
+		counts := map[int]int{}
+		mode := 0
+		for _, record := range records {
+			counts[len(record)]++
+			if counts[len(record)] > counts[mode] || (counts[len(record)] == counts[mode] && len(record) > mode) {
+				mode = len(record)
+			}
+		}
+		if mode < 2 {
+			continue
+		}
+		ratio := float64(counts[mode]) / float64(len(records))
+		if ratio > bestRatio || (ratio == bestRatio && mode > bestFields) {
+			comma, rows = candidate, records
+			bestRatio, bestFields = ratio, mode
+		}
+	}
+	return comma, rows
+}
+
+func sniffRecords(text string, comma rune) [][]string {
+	r := csv.NewReader(strings.NewReader(text))
+	r.Comma = comma
+	r.LazyQuotes = true
+	r.FieldsPerRecord = -1
+
+	var records [][]string
+	for len(records) < sniffRows {
+		record, err := r.Read()
+		if err != nil {
+			break
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// sniffHeader reports whether the first row looks like a header row. Each column votes for a header
+// when its data rows are numbers but its first cell is not, or when its data rows have the same length
+// but its first cell has another length, and against it when its first cell looks like the data rows.
+// Undecided votes give a header, as parsing a header row as data is worse than skipping a data row.
+func sniffHeader(rows [][]string) bool {
+	if len(rows) == 0 {
+		return false
+	}
+	header := rows[0]
+	if len(rows) == 1 {
+		for _, cell := range header {
+			if isNumber(cell) || strings.TrimSpace(cell) == "" {
+				return false
+			}
+		}
+		return true
+	}
+
+	votes := 0
+	for j, cell := range header {
+		numeric := true
+		length := -1
+		for _, row := range rows[1:] {
+			if j >= len(row) {
+				continue
+			}
+			if !isNumber(row[j]) {
+				numeric = false
+			}
+			if length == -1 {
+				length = utf8.RuneCountInString(row[j])
+			} else if length != utf8.RuneCountInString(row[j]) {
+				length = -2
+			}
+		}
+		switch {
+		case numeric && !isNumber(cell):
+			votes++
+		case numeric:
+			votes--
+		case length >= 0 && length != utf8.RuneCountInString(cell):
+			votes++
+		case length >= 0:
+			votes--
+		}
+	}
+	return votes >= 0
+}
+
+func isNumber(text string) bool {
+	_, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+	return err == nil
+}
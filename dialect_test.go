@@ -0,0 +1,65 @@
+package csvx_test
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected csvx.Dialect
+	}{
+		{"comma", "ID,Name,Age\n1,N1,3.14\n2,N2,3.14\n", csvx.Dialect{Comma: ',', LineTerminator: "\n", Header: true}},
+		{"semicolon", "\ufeff\"ID\";\"Name, Space\";\"Age\"\r\n\"1\";\"N1\";\"3.14\"\r\n", csvx.Dialect{Comma: ';', Quote: true, LineTerminator: "\r\n", BOM: true, Header: true}},
+		{"tab", "1\tN1\t3.14\n2\tN2\t3.14\n", csvx.Dialect{Comma: '\t', LineTerminator: "\n"}},
+		{"pipe", "ID|Name\n1|N1\n", csvx.Dialect{Comma: '|', LineTerminator: "\n", Header: true}},
+		{"text", "name\tcity\nbob\tparis\nal\tnyc\n", csvx.Dialect{Comma: '\t', LineTerminator: "\n", Header: true}},
+		{"sep line", "sep=;\r\nID;Name\r\n1;N1\r\n", csvx.Dialect{Comma: ';', LineTerminator: "\r\n", Header: true, SepLine: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			d, err := csvx.Sniff(strings.NewReader(tt.input))
+
+			// Then
+			if err != nil || d != tt.expected {
+				t.Errorf("Sniff error: %+v %v", d, err)
+			}
+		})
+	}
+}
+
+func TestSniffRewind(t *testing.T) {
+	// Given
+	r := strings.NewReader("ID;Name\n1;N1\n")
+
+	// When
+	_, err := csvx.Sniff(r)
+	rest, _ := io.ReadAll(r)
+
+	// Then
+	if err != nil || string(rest) != "ID;Name\n1;N1\n" {
+		t.Error("Sniff must rewind the reader", string(rest))
+	}
+}
+
+func TestSniffReader(t *testing.T) {
+	// Given
+	input := "\ufeffsep=;\nID;Name Space;Age\n1;N1;3.5\n"
+
+	// When
+	d, r, err := csvx.SniffReader(io.MultiReader(strings.NewReader(input)))
+	rows := csvx.Reader(csv.NewReader(r), d.Reader)
+	s := csvx.Parser[StructType](rows, d.Parser)
+
+	// Then
+	if err != nil || len(s) != 1 || s[0].Name != "N1" || s[0].Age != 3.5 {
+		t.Error("Parse sniffed dialect error", d, s, err)
+	}
+}
@@ -0,0 +1,269 @@
+package csvx
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"sync"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding is a character encoding of csv files, transcoded from and to UTF-8 with built-in tables.
+type Encoding int
+
+const (
+	// UTF8 is the default encoding.
+	UTF8 Encoding = iota
+	// UTF16LE is the encoding of Excel "Unicode Text" exports.
+	UTF16LE
+	// UTF16BE is UTF-16 big endian.
+	UTF16BE
+	// Windows874 is the Thai encoding of Windows, a superset of TIS-620.
+	Windows874
+	// TIS620 is the Thai industrial standard encoding, also known as ISO-8859-11.
+	TIS620
+	// ISO88591 is Latin-1.
+	ISO88591
+	// Windows1252 is the western European encoding of Windows, a superset of Latin-1.
+	Windows1252
+)
+
+var (
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// windows1252 maps the bytes 0x80 to 0x9F of Windows-1252, the other bytes being Latin-1.
+var windows1252 = [32]rune{
+	0x20AC, utf8.RuneError, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, utf8.RuneError, 0x017D, utf8.RuneError,
+	utf8.RuneError, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, utf8.RuneError, 0x017E, 0x0178,
+}
+
+// thaiRune returns the rune of the byte b >= 0x80 in TIS-620, or in Windows-874 when windows is true.
+func thaiRune(b byte, windows bool) rune {
+	switch {
+	case b >= 0xA1 && b <= 0xDA, b >= 0xDF && b <= 0xFB:
+		// Thai letters, vowels, tone marks and digits are in the same order as in Unicode
+		return rune(b) - 0xA0 + 0x0E00
+	case b == 0xA0:
+		return 0x00A0
+	case !windows && b < 0xA0:
+		return rune(b)
+	case windows && b == 0x80:
+		return 0x20AC
+	case windows && b == 0x85:
+		return 0x2026
+	case windows && b >= 0x91 && b <= 0x97:
+		return windows1252[b-0x80]
+	}
+	return utf8.RuneError
+}
+
+// decodeRune returns the rune of the byte b in the single byte encoding enc.
+func (enc Encoding) decodeRune(b byte) rune {
+	if b < 0x80 {
+		return rune(b)
+	}
+	switch enc {
+	case Windows874:
+		return thaiRune(b, true)
+	case TIS620:
+		return thaiRune(b, false)
+	case Windows1252:
+		if b < 0xA0 {
+			return windows1252[b-0x80]
+		}
+	}
+	return rune(b)
+}
+
+// TranscodeReader returns a reader that transcodes r from enc to UTF-8. A UTF-8 or UTF-16 byte order
+// mark at the start of r takes precedence over enc and is removed.
+//
+//	rows := csvx.Reader(csv.NewReader(csvx.TranscodeReader(file, csvx.Windows874)))
+func TranscodeReader(r io.Reader, enc Encoding) io.Reader {
+	return &transcodeReader{src: r, enc: enc, detect: true}
+}
+
+// Decode transcodes data from enc to UTF-8, see TranscodeReader.
+//
+//	rows := csvx.ByteReader(csvx.Decode(data, csvx.TIS620))
+func Decode(data []byte, enc Encoding) []byte {
+	decoded, _ := io.ReadAll(TranscodeReader(bytes.NewReader(data), enc))
+	return decoded
+}
+
+type transcodeReader struct {
+	src    io.Reader
+	enc    Encoding
+	detect bool
+	in     []byte
+	out    []byte
+	err    error
+}
+
+func (t *transcodeReader) Read(p []byte) (int, error) {
+	for len(t.out) == 0 {
+		if t.err != nil {
+			return 0, t.err
+		}
+		buf := make([]byte, 4096)
+		n, err := t.src.Read(buf)
+		t.in = append(t.in, buf[:n]...)
+		t.err = err
+		if t.detect {
+			if len(t.in) < len(Utf8BOM) && t.err == nil {
+				continue
+			}
+			t.detectBOM()
+		}
+		t.decode()
+	}
+	n := copy(p, t.out)
+	t.out = t.out[n:]
+	return n, nil
+}
+
+func (t *transcodeReader) detectBOM() {
+	t.detect = false
+	switch {
+	case bytes.HasPrefix(t.in, []byte(Utf8BOM)):
+		t.enc = UTF8
+		t.in = t.in[len(Utf8BOM):]
+	case bytes.HasPrefix(t.in, utf16LEBOM):
+		t.enc = UTF16LE
+		t.in = t.in[len(utf16LEBOM):]
+	case bytes.HasPrefix(t.in, utf16BEBOM):
+		t.enc = UTF16BE
+		t.in = t.in[len(utf16BEBOM):]
+	}
+}
+
+// decode moves the input that can be decoded to the output.
+func (t *transcodeReader) decode() {
+	eof := t.err != nil
+	switch t.enc {
+	case UTF8:
+		t.out, t.in = t.in, nil
+	case UTF16LE, UTF16BE:
+		units := make([]uint16, len(t.in)/2)
+		for i := range units {
+			if t.enc == UTF16LE {
+				units[i] = uint16(t.in[2*i]) | uint16(t.in[2*i+1])<<8
+			} else {
+				units[i] = uint16(t.in[2*i])<<8 | uint16(t.in[2*i+1])
+			}
+		}
+		// Keep a high surrogate until its pair is read
+		if !eof && len(units) > 0 && utf16.IsSurrogate(rune(units[len(units)-1])) && units[len(units)-1] < 0xDC00 {
+			units = units[:len(units)-1]
+		}
+		t.in = t.in[2*len(units):]
+		if eof && len(t.in) > 0 {
+			units = append(units, 0xFFFD)
+			t.in = nil
+		}
+		t.out = []byte(string(utf16.Decode(units)))
+	default:
+		out := make([]byte, 0, len(t.in))
+		for _, b := range t.in {
+			out = utf8.AppendRune(out, t.enc.decodeRune(b))
+		}
+		t.out, t.in = out, nil
+	}
+}
+
+// String returns the name of the encoding.
+func (enc Encoding) String() string {
+	switch enc {
+	case UTF8:
+		return "UTF-8"
+	case UTF16LE:
+		return "UTF-16LE"
+	case UTF16BE:
+		return "UTF-16BE"
+	case Windows874:
+		return "Windows-874"
+	case TIS620:
+		return "TIS-620"
+	case ISO88591:
+		return "ISO-8859-1"
+	case Windows1252:
+		return "Windows-1252"
+	}
+	return "Encoding(" + strconv.Itoa(int(enc)) + ")"
+}
+
+// BOM returns the byte order mark of the encoding, or nil for the encodings without one.
+func (enc Encoding) BOM() []byte {
+	switch enc {
+	case UTF8:
+		return []byte(Utf8BOM)
+	case UTF16LE:
+		return utf16LEBOM
+	case UTF16BE:
+		return utf16BEBOM
+	}
+	return nil
+}
+
+var (
+	encodeTables     = map[Encoding]map[rune]byte{}
+	encodeTablesOnce sync.Once
+)
+
+// encodeTable returns the byte of each non-ASCII rune of the single byte encoding enc.
+func (enc Encoding) encodeTable() map[rune]byte {
+	encodeTablesOnce.Do(func() {
+		for _, e := range []Encoding{Windows874, TIS620, ISO88591, Windows1252} {
+			table := map[rune]byte{}
+			for b := 0x80; b <= 0xFF; b++ {
+				if r := e.decodeRune(byte(b)); r != utf8.RuneError {
+					table[r] = byte(b)
+				}
+			}
+			encodeTables[e] = table
+		}
+	})
+	return encodeTables[enc]
+}
+
+// Encode transcodes the UTF-8 text to enc, without a byte order mark. It returns an *EncodeError
+// for the first character that enc cannot represent.
+//
+//	data, err := csvx.Encode(csv, csvx.Windows874)
+func Encode(text string, enc Encoding) ([]byte, error) {
+	switch enc {
+	case UTF8:
+		return []byte(text), nil
+	case UTF16LE, UTF16BE:
+		units := utf16.Encode([]rune(text))
+		data := make([]byte, 0, 2*len(units))
+		for _, u := range units {
+			if enc == UTF16LE {
+				data = append(data, byte(u), byte(u>>8))
+			} else {
+				data = append(data, byte(u>>8), byte(u))
+			}
+		}
+		return data, nil
+	}
+
+	table := enc.encodeTable()
+	data := make([]byte, 0, len(text))
+	for i, r := range text {
+		if r < 0x80 {
+			data = append(data, byte(r))
+			continue
+		}
+		b, ok := table[r]
+		if !ok {
+			return nil, &EncodeError{Rune: r, Offset: i, Encoding: enc}
+		}
+		data = append(data, b)
+	}
+	return data, nil
+}
@@ -0,0 +1,116 @@
+package csvx_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"testing/iotest"
+	"unicode/utf16"
+
+	"github.com/prongbang/csvx"
+)
+
+func utf16LE(text string) []byte {
+	data := []byte{0xFF, 0xFE}
+	for _, u := range utf16.Encode([]rune(text)) {
+		data = append(data, byte(u), byte(u>>8))
+	}
+	return data
+}
+
+func TestByteReaderUTF16(t *testing.T) {
+	// Given
+	data := utf16LE("ID\tName\n1\tสวัสดี 😀\n")
+
+	// When
+	rows := csvx.ByteReader(data, func(r *csv.Reader) {
+		r.Comma = '\t'
+	})
+
+	// Then
+	expected := [][]string{{"ID", "Name"}, {"1", "สวัสดี 😀"}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Error("Read UTF-16 error", rows)
+	}
+}
+
+func TestTranscodeReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		enc      csvx.Encoding
+		expected string
+	}{
+		{"windows-874", []byte{'I', 'D', ',', 0xCA, 0xC7, 0xD1, 0xCA, 0xB4, 0xD5, 0x80}, csvx.Windows874, "ID,สวัสดี€"},
+		{"tis-620", []byte{0xCA, 0xC7, 0xD1, 0xCA, 0xB4, 0xD5}, csvx.TIS620, "สวัสดี"},
+		{"latin-1", []byte{'c', 'a', 'f', 0xE9, 0x80}, csvx.ISO88591, "café\u0080"},
+		{"windows-1252", []byte{'c', 'a', 'f', 0xE9, 0x80}, csvx.Windows1252, "café€"},
+		{"utf-8 bom", []byte("\ufeffID"), csvx.Windows874, "ID"},
+		{"utf-16 split surrogate", utf16LE("a😀b"), csvx.UTF8, "a😀b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			data, err := io.ReadAll(csvx.TranscodeReader(iotest.OneByteReader(bytes.NewReader(tt.data)), tt.enc))
+
+			// Then
+			if err != nil || string(data) != tt.expected {
+				t.Errorf("Transcode error: %q %v", data, err)
+			}
+		})
+	}
+}
+
+func TestDecode(t *testing.T) {
+	// Given
+	data := []byte{'I', 'D', ',', 'N', '\n', '1', ',', 0xCA, 0xC7, 0xD1, 0xCA, 0xB4, 0xD5, '\n'}
+
+	// When
+	rows := csvx.ByteReader(csvx.Decode(data, csvx.Windows874))
+
+	// Then
+	if rows[1][1] != "สวัสดี" {
+		t.Error("Decode error", rows)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		enc      csvx.Encoding
+		expected []byte
+	}{
+		{"windows-874", "ID,สวัสดี€", csvx.Windows874, []byte{'I', 'D', ',', 0xCA, 0xC7, 0xD1, 0xCA, 0xB4, 0xD5, 0x80}},
+		{"utf-16le", "a😀", csvx.UTF16LE, utf16LE("a😀")[2:]},
+		{"utf-16be", "a", csvx.UTF16BE, []byte{0x00, 'a'}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			data, err := csvx.Encode(tt.text, tt.enc)
+
+			// Then
+			if err != nil || !bytes.Equal(data, tt.expected) {
+				t.Errorf("Encode error: %v %v", data, err)
+			}
+		})
+	}
+}
+
+func TestEncodeUnencodable(t *testing.T) {
+	// When
+	_, err := csvx.Encode("ID,日本", csvx.Windows874)
+
+	// Then
+	var encodeErr *csvx.EncodeError
+	if !errors.As(err, &encodeErr) || encodeErr.Rune != '日' || encodeErr.Offset != 3 {
+		t.Fatal("Encode error", err)
+	}
+	if err.Error() != `character '日' (U+65E5) at offset 3 cannot be encoded in Windows-874` {
+		t.Error("Encode message", err)
+	}
+}
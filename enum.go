@@ -0,0 +1,146 @@
+package csvx
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// enum maps the labels used in csv files to the values of a Go type.
+type enum struct {
+	values map[string]reflect.Value
+	folded map[string]reflect.Value
+	labels map[interface{}]string
+}
+
+var enumRegistry sync.Map
+
+func newEnum() *enum {
+	return &enum{
+		values: map[string]reflect.Value{},
+		folded: map[string]reflect.Value{},
+		labels: map[interface{}]string{},
+	}
+}
+
+// add maps label to value. The first label added for a value is the one used by Convert.
+func (e *enum) add(label string, value reflect.Value) {
+	if _, ok := e.values[label]; !ok {
+		e.values[label] = value
+	}
+	if _, ok := e.folded[strings.ToLower(label)]; !ok {
+		e.folded[strings.ToLower(label)] = value
+	}
+	if key, ok := enumKey(value); ok {
+		if _, ok := e.labels[key]; !ok {
+			e.labels[key] = label
+		}
+	}
+}
+
+// parse sets v to the value mapped by the label in text.
+func (e *enum) parse(v reflect.Value, text string, caseInsensitive bool) error {
+	text = strings.TrimSpace(text)
+	value, ok := e.values[text]
+	if !ok && caseInsensitive {
+		value, ok = e.folded[strings.ToLower(text)]
+	}
+	if !ok {
+		return fmt.Errorf("unknown enum label %q", text)
+	}
+	v.Set(value)
+	return nil
+}
+
+// label returns the label mapped to the value v.
+func (e *enum) label(v reflect.Value) (string, bool) {
+	key, ok := enumKey(v)
+	if !ok {
+		return "", false
+	}
+	label, ok := e.labels[key]
+	return label, ok
+}
+
+// enumKey returns the key of the value v in the labels. Integers and strings are read by kind,
+// as the value of an unexported field cannot be read with Interface.
+func enumKey(v reflect.Value) (interface{}, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint(), true
+	case reflect.String:
+		return v.String(), true
+	}
+	if !v.CanInterface() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// parseEnumTag parses an `enum:"Active=1,Suspended=2"` tag for a field of type t.
+func parseEnumTag(t reflect.Type, tag string) (*enum, error) {
+	e := newEnum()
+	for _, pair := range strings.Split(tag, ",") {
+		label, raw, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid enum entry %q", pair)
+		}
+		label = strings.TrimSpace(label)
+		raw = strings.TrimSpace(raw)
+
+		value := reflect.New(t).Elem()
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n, err := strconv.ParseInt(raw, 10, t.Bits())
+			if err != nil {
+				return nil, fmt.Errorf("invalid enum entry %q: %w", pair, err)
+			}
+			value.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n, err := strconv.ParseUint(raw, 10, t.Bits())
+			if err != nil {
+				return nil, fmt.Errorf("invalid enum entry %q: %w", pair, err)
+			}
+			value.SetUint(n)
+		case reflect.String:
+			value.SetString(raw)
+		default:
+			return nil, fmt.Errorf("enum tag is not supported on %s", t)
+		}
+		e.add(label, value)
+	}
+	return e, nil
+}
+
+// RegisterEnum registers the csv labels of the enum type T. Fields of type T or *T are then
+// parsed from and converted to these labels without needing an `enum` tag. When several labels
+// map to the same value, Convert writes the first one in sorted order.
+//
+//	type Status int
+//
+//	csvx.RegisterEnum(map[string]Status{"Active": 1, "Suspended": 2})
+func RegisterEnum[T comparable](labels map[string]T) {
+	keys := make([]string, 0, len(labels))
+	for label := range labels {
+		keys = append(keys, label)
+	}
+	sort.Strings(keys)
+
+	e := newEnum()
+	for _, label := range keys {
+		e.add(label, reflect.ValueOf(labels[label]))
+	}
+	enumRegistry.Store(reflect.TypeOf((*T)(nil)).Elem(), e)
+}
+
+func registeredEnum(t reflect.Type) *enum {
+	if e, ok := enumRegistry.Load(t); ok {
+		return e.(*enum)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package csvx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+type Status int
+
+type Level int
+
+type StructEnum struct {
+	ID     int    `header:"ID" no:"1"`
+	Status Status `header:"Status" no:"2" enum:"Active=1,Suspended=2"`
+	Level  *Level `header:"Level" no:"3"`
+}
+
+func init() {
+	csvx.RegisterEnum(map[string]Level{"Low": 1, "High": 2})
+}
+
+func TestParserEnum(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"ID", "Status", "Level"},
+		{"1", "Active", "High"},
+		{"2", "Suspended", ""},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructEnum](rows)
+
+	// Then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s[0].Status != 1 || s[1].Status != 2 {
+		t.Error("Parse enum tag error", s)
+	}
+	if s[0].Level == nil || *s[0].Level != 2 || s[1].Level != nil {
+		t.Error("Parse registered enum error", s)
+	}
+}
+
+func TestParserEnumCaseInsensitive(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"ID", "Status", "Level"},
+		{"1", "active", "low"},
+	}
+
+	// When
+	_, err := csvx.TryParser[StructEnum](rows)
+	s, iErr := csvx.TryParser[StructEnum](rows, func(o *csvx.ParserOptions) {
+		o.EnumCaseInsensitive = true
+	})
+
+	// Then
+	var parseErrors csvx.ParseErrors
+	if !errors.As(err, &parseErrors) || len(parseErrors) != 2 {
+		t.Error("Unknown labels must be reported:", err)
+	}
+	if iErr != nil || s[0].Status != 1 || *s[0].Level != 1 {
+		t.Error("Parse enum case insensitive error", iErr, s)
+	}
+}
+
+func TestConvertEnum(t *testing.T) {
+	// Given
+	high := Level(2)
+	m := []StructEnum{{ID: 1, Status: 1, Level: &high}, {ID: 2, Status: 9}}
+	expected := csvx.Utf8BOM + `"ID","Status","Level"
+"1","Active","High"
+"2","9",""`
+
+	// When
+	result := csvx.Convert(m)
+
+	// Then
+	if result != expected {
+		t.Error("Convert enum error:", result)
+	}
+}
+
+type StructUnexportedEnum struct {
+	ID     int    `header:"ID" no:"1"`
+	status Status `header:"Status" no:"2" enum:"Active=1,Suspended=2"`
+	level  Level  `header:"Level" no:"3"`
+}
+
+func TestConvertUnexportedEnum(t *testing.T) {
+	// Given
+	m := []StructUnexportedEnum{{ID: 1, status: 2, level: 1}}
+	expected := csvx.Utf8BOM + `"ID","Status","Level"
+"1","Suspended","Low"`
+
+	// When
+	result := csvx.Convert(m)
+
+	// Then
+	if result != expected {
+		t.Error("Convert unexported enum error:", result)
+	}
+}
+
+func TestParserUnexportedEnum(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"ID", "Status", "Level"},
+		{"1", "Suspended", ""},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructUnexportedEnum](rows)
+
+	// Then
+	var errs csvx.ParseErrors
+	if !errors.As(err, &errs) || len(errs) != 2 || errs[0].Field != "status" || s[0].ID != 1 || s[0].status != 0 {
+		t.Error("Parse unexported enum error", s, err)
+	}
+}
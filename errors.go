@@ -0,0 +1,105 @@
+package csvx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ParseError describes a cell that could not be converted into its struct field.
+type ParseError struct {
+	// Row is the index of the record in the parsed rows.
+	Row int
+	// Column is the header of the cell.
+	Column string
+	// Field is the name of the struct field.
+	Field string
+	// Value is the text of the cell.
+	Value string
+	// Err is the underlying conversion error.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("row %d, column %q: %v", e.Row, e.Column, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// ParseErrors is the list of errors returned by TryParser.
+type ParseErrors []*ParseError
+
+func (e ParseErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// HeaderError describes a header row that does not match the struct.
+type HeaderError struct {
+	// Missing lists the required columns that are not in the header.
+	Missing []string
+	// Unexpected lists the columns that are not mapped to the struct.
+	Unexpected []string
+	// Duplicate lists the columns that appear more than once, or that set the same field as a previous column
+	// through an alias. Empty headers are ignored.
+	Duplicate []string
+	// Suggestions maps the columns that are not mapped to the struct to the closest struct header, for typos.
+	// It only explains the Unexpected columns and the Missing columns, it never adds an error.
+	Suggestions map[string]string
+}
+
+func (e *HeaderError) Error() string {
+	var messages []string
+	for _, name := range e.Missing {
+		if head, ok := e.suggestionFor(name); ok {
+			messages = append(messages, fmt.Sprintf("missing column %q (did you mean %q?)", name, head))
+		} else {
+			messages = append(messages, fmt.Sprintf("missing column %q", name))
+		}
+	}
+	for _, name := range e.Unexpected {
+		if suggestion, ok := e.Suggestions[name]; ok {
+			messages = append(messages, fmt.Sprintf("unknown column %q (did you mean %q?)", name, suggestion))
+		} else {
+			messages = append(messages, fmt.Sprintf("unknown column %q", name))
+		}
+	}
+	for _, name := range e.Duplicate {
+		messages = append(messages, fmt.Sprintf("duplicate column %q", name))
+	}
+	return "invalid header: " + strings.Join(messages, "; ")
+}
+
+// suggestionFor returns the column of the header that looks like a typo of the missing column name.
+func (e *HeaderError) suggestionFor(name string) (string, bool) {
+	heads := make([]string, 0, len(e.Suggestions))
+	for head, suggestion := range e.Suggestions {
+		if suggestion == name {
+			heads = append(heads, head)
+		}
+	}
+	if len(heads) == 0 {
+		return "", false
+	}
+	sort.Strings(heads)
+	return heads[0], true
+}
+
+// EncodeError reports a character that cannot be represented in the output encoding.
+type EncodeError struct {
+	// Rune is the character that cannot be encoded.
+	Rune rune
+	// Offset is the byte offset of the character in the UTF-8 text.
+	Offset int
+	// Encoding is the output encoding.
+	Encoding Encoding
+}
+
+func (e *EncodeError) Error() string {
+	return fmt.Sprintf("character %q (%U) at offset %d cannot be encoded in %s", e.Rune, e.Rune, e.Offset, e.Encoding)
+}
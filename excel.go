@@ -0,0 +1,36 @@
+package csvx
+
+import "strings"
+
+// ExcelDialect returns the dialect of csv files that open in Excel without altering the data: CRLF line endings,
+// a UTF-8 BOM, double quotes, and numbers that Excel would alter written as text.
+// Set SepLine on the returned dialect to also write an Excel "sep=," line.
+//
+//	csv, err := csvx.ConvertWithOptions(m, csvx.ExcelDialect().Convert)
+func ExcelDialect() Dialect {
+	return Dialect{
+		Comma:          ',',
+		Quote:          true,
+		LineTerminator: "\r\n",
+		BOM:            true,
+		Header:         true,
+		ProtectText:    true,
+	}
+}
+
+// excelDigits is the number of digits above which Excel displays a number in scientific notation.
+const excelDigits = 11
+
+// excelAltered reports whether Excel alters the text when it is read as a number: the leading zeros
+// of "00123" are dropped and "1234567890123456" is displayed in scientific notation and rounded to 15 digits.
+func excelAltered(text string) bool {
+	if text == "" || strings.Trim(text, "0123456789") != "" {
+		return false
+	}
+	return (len(text) > 1 && text[0] == '0') || len(text) > excelDigits
+}
+
+// excelText returns the text as an Excel text formula, such as ="00123".
+func excelText(text string) string {
+	return `="` + strings.ReplaceAll(text, `"`, `""`) + `"`
+}
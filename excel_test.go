@@ -0,0 +1,78 @@
+package csvx_test
+
+import (
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+type Account struct {
+	Number string `header:"Number" no:"1"`
+	Branch string `header:"Branch" no:"2"`
+	Code   string `header:"Code" no:"3" text:""`
+	Name   string `header:"Name" no:"4"`
+}
+
+func TestConvertExcelDialect(t *testing.T) {
+	// Given
+	m := []Account{{Number: "1234567890123456", Branch: "00123", Code: "12", Name: "N1"}, {Number: "123", Branch: "0", Name: "N2"}}
+	expected := csvx.Utf8BOM + "\"Number\",\"Branch\",\"Code\",\"Name\"\r\n" +
+		"\"=\"\"1234567890123456\"\"\",\"=\"\"00123\"\"\",\"=\"\"12\"\"\",\"N1\"\r\n" +
+		"\"123\",\"0\",\"\",\"N2\""
+
+	// When
+	result, err := csvx.ConvertWithOptions(m, csvx.ExcelDialect().Convert)
+
+	// Then
+	if err != nil || result != expected {
+		t.Errorf("Convert error: %q %v", result, err)
+	}
+}
+
+func TestConvertExcelDialectSepLine(t *testing.T) {
+	// Given
+	m := []Account{{Number: "007", Name: "N1"}}
+	dialect := csvx.ExcelDialect()
+	dialect.Comma = ';'
+	dialect.Quote = false
+	dialect.SepLine = true
+
+	// When
+	result, _ := csvx.ConvertWithOptions(m, dialect.Convert)
+
+	// Then
+	if result != csvx.Utf8BOM+"sep=;\r\nNumber;Branch;Code;Name\r\n=\"007\";;;N1" {
+		t.Errorf("Convert error: %q", result)
+	}
+	if excel := csvx.ExcelDialect(); excel.Comma != ',' || !excel.Quote || excel.SepLine {
+		t.Error("ExcelDialect must not be altered by a copy", excel)
+	}
+}
+
+func TestConvertTextTag(t *testing.T) {
+	// Given
+	m := []Account{{Number: "007", Code: "1"}}
+
+	// When
+	result := csvx.Convert(m, true)
+
+	// Then
+	if result != csvx.Utf8BOM+"Number,Branch,Code,Name\n007,,=\"1\"," {
+		t.Errorf("Convert error: %q", result)
+	}
+}
+
+func TestManualConvertExcelDialect(t *testing.T) {
+	// Given
+	m := []Account{{Number: "00123", Name: "N1"}}
+
+	// When
+	result, err := csvx.ManualConvertWithOptions(m, []string{"Number", "Name"}, func(data Account) []string {
+		return []string{data.Number, data.Name}
+	}, csvx.ExcelDialect().Convert)
+
+	// Then
+	if err != nil || result != csvx.Utf8BOM+"Number,Name\r\n\"=\"\"00123\"\"\",N1\r\n" {
+		t.Errorf("ManualConvert error: %q %v", result, err)
+	}
+}
@@ -0,0 +1,227 @@
+package csvx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrRecordLength is the error of the records that do not have the length of the layout in FixedOptions.Strict mode.
+var ErrRecordLength = errors.New("invalid record length")
+
+// FixedOptions configures ParseFixed and ConvertFixed.
+type FixedOptions struct {
+	// Strict requires every record to be exactly RecordLength characters long. ParseFixed reports the other
+	// records as a *ParseError wrapping ErrRecordLength.
+	Strict bool
+
+	// RecordLength is the length of a record, the end of the last field when zero. ConvertFixed pads
+	// the records to this length.
+	RecordLength int
+
+	// LineTerminator ends the records written by ConvertFixed, "\n" when empty.
+	LineTerminator string
+
+	// EnumCaseInsensitive matches enum labels regardless of letter case, see ParserOptions.
+	EnumCaseInsensitive bool
+
+	// ExtendedIntegers accepts base prefixes, underscores and scientific notation in integer fields, see ParserOptions.
+	ExtendedIntegers bool
+}
+
+func fixedOptions(options []func(o *FixedOptions)) *FixedOptions {
+	o := &FixedOptions{}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+// layout is the fixed-width fields of a struct, sorted by position.
+type layout struct {
+	columns []*column
+	length  int
+}
+
+// fixedLayout returns the fields of t that have a `pos` tag. It returns an error if a tag is invalid
+// or if two fields overlap.
+func fixedLayout(t reflect.Type, o *FixedOptions) (*layout, error) {
+	l := &layout{}
+	for _, c := range fixedColumnsOf(t) {
+		if c.err != nil {
+			return nil, c.err
+		}
+		l.columns = append(l.columns, c)
+	}
+	if len(l.columns) == 0 {
+		return nil, fmt.Errorf("%s has no field with a pos tag", t)
+	}
+
+	sort.SliceStable(l.columns, func(i, j int) bool {
+		return l.columns[i].pos < l.columns[j].pos
+	})
+	for i, c := range l.columns {
+		if i > 0 {
+			if prev := l.columns[i-1]; prev.pos+prev.width > c.pos {
+				return nil, fmt.Errorf("field %s overlaps field %s", c.name, prev.name)
+			}
+		}
+		l.length = c.pos + c.width - 1
+	}
+	if o.RecordLength > 0 {
+		if o.RecordLength < l.length {
+			return nil, fmt.Errorf("record length %d is shorter than the fields, %d characters", o.RecordLength, l.length)
+		}
+		l.length = o.RecordLength
+	}
+	return l, nil
+}
+
+// ParseFixed parses a fixed-width file into structs. Each field with a `pos` tag, its first character
+// starting at 1, is read from `width` characters, with the `pad` character (a space by default) trimmed
+// on the right for `align:"left"` (the default) or on the left for `align:"right"`.
+// The values are converted like Parser and ParseFixed returns a ParseErrors listing every field that could
+// not be converted, with the index of its line as Row.
+//
+//	type Payment struct {
+//		Account string  `pos:"1" width:"10"`
+//		Amount  float64 `pos:"11" width:"12" align:"right" pad:"0"`
+//	}
+//
+//	s, err := csvx.ParseFixed[Payment](data, func(o *csvx.FixedOptions) {
+//		o.Strict = true
+//	})
+func ParseFixed[T any](data []byte, options ...func(o *FixedOptions)) ([]T, error) {
+	var structs []T
+
+	o := fixedOptions(options)
+	l, err := fixedLayout(reflect.TypeOf(model[T]{}.Data), o)
+	if err != nil {
+		return structs, err
+	}
+
+	text := strings.TrimPrefix(string(data), Utf8BOM)
+	lines := strings.Split(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	po := &ParserOptions{EnumCaseInsensitive: o.EnumCaseInsensitive, ExtendedIntegers: o.ExtendedIntegers}
+	var errs ParseErrors
+	for i, line := range lines {
+		record := []rune(strings.TrimSuffix(line, "\r"))
+		if o.Strict && len(record) != l.length {
+			errs = append(errs, &ParseError{
+				Row:   i,
+				Value: string(record),
+				Err:   fmt.Errorf("%w: %d characters, expected %d", ErrRecordLength, len(record), l.length),
+			})
+			continue
+		}
+
+		var value T
+		structValue := reflect.ValueOf(&value).Elem()
+		for _, c := range l.columns {
+			cell := c.fixedCell(record)
+			field, err := c.fieldOf(structValue)
+			if err == nil {
+				err = c.parseValue(field, cell, po)
+			}
+			if err != nil {
+				errs = append(errs, &ParseError{
+					Row:    i,
+					Column: c.header,
+					Field:  c.name,
+					Value:  cell,
+					Err:    err,
+				})
+			}
+		}
+		structs = append(structs, value)
+	}
+
+	if len(errs) > 0 {
+		return structs, errs
+	}
+	return structs, nil
+}
+
+// ConvertFixed writes the structs as a fixed-width file, the layout of ParseFixed. The values are formatted
+// like Convert, nil pointers being written with their `default` tag, and padded to their width.
+// It returns an error if a value is longer than its width.
+//
+//	data, err := csvx.ConvertFixed(payments)
+func ConvertFixed[T any](data []T, options ...func(o *FixedOptions)) (string, error) {
+	o := fixedOptions(options)
+	l, err := fixedLayout(reflect.TypeOf(data).Elem(), o)
+	if err != nil {
+		return "", err
+	}
+
+	lineTerminator := o.LineTerminator
+	if lineTerminator == "" {
+		lineTerminator = "\n"
+	}
+
+	var buffer strings.Builder
+	for i, d := range data {
+		el := reflect.ValueOf(&d).Elem()
+		record := []rune(strings.Repeat(" ", l.length))
+		for _, c := range l.columns {
+			value := c.value(el)
+			cell, err := c.fixedValue(value)
+			if err != nil {
+				return "", fmt.Errorf("row %d, field %s: %w", i, c.name, err)
+			}
+			copy(record[c.pos-1:], cell)
+		}
+		buffer.WriteString(string(record))
+		buffer.WriteString(lineTerminator)
+	}
+	return buffer.String(), nil
+}
+
+// fixedCell returns the text of the field in the record, without its padding.
+func (c *column) fixedCell(record []rune) string {
+	start, end := c.pos-1, c.pos-1+c.width
+	if start >= len(record) {
+		return ""
+	}
+	if end > len(record) {
+		end = len(record)
+	}
+	cell := string(record[start:end])
+	if c.align == "left" {
+		return strings.TrimRight(cell, string(c.pad))
+	}
+
+	// Zero padded numbers keep their sign first, as in -000120
+	sign := ""
+	if c.pad == '0' && cell != "" && (cell[0] == '-' || cell[0] == '+') {
+		sign, cell = cell[:1], cell[1:]
+	}
+	cell = strings.TrimLeft(cell, string(c.pad))
+	if sign != "" && cell == "" {
+		return "0"
+	}
+	return sign + cell
+}
+
+// fixedValue pads the value to the width of the field.
+func (c *column) fixedValue(value string) ([]rune, error) {
+	n := utf8.RuneCountInString(value)
+	if n > c.width {
+		return nil, fmt.Errorf("value %q is longer than %d characters", value, c.width)
+	}
+	padding := strings.Repeat(string(c.pad), c.width-n)
+	if c.align == "left" {
+		return []rune(value + padding), nil
+	}
+	if c.pad == '0' && value != "" && (value[0] == '-' || value[0] == '+') {
+		return []rune(value[:1] + padding + value[1:]), nil
+	}
+	return []rune(padding + value), nil
+}
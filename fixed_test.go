@@ -0,0 +1,132 @@
+package csvx_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+type Transfer struct {
+	Account string  `pos:"1" width:"10"`
+	Name    string  `header:"Name" pos:"11" width:"8"`
+	Amount  float64 `pos:"19" width:"9" align:"right" pad:"0"`
+	Count   int     `pos:"28" width:"3" align:"right"`
+	Ref     *string `pos:"32" width:"4" default:"NONE"`
+	Status  Status  `pos:"36" width:"6" enum:"Active=1,Suspended=2"`
+}
+
+func TestParseFixed(t *testing.T) {
+	// Given
+	data := []byte("0012345678สมชาย   000120.50  7 AB12Active\n" +
+		"9876543210Jane    -00000.25 12     Active\n")
+
+	// When
+	s, err := csvx.ParseFixed[Transfer](data)
+
+	// Then
+	if err != nil || len(s) != 2 {
+		t.Fatal("ParseFixed error", s, err)
+	}
+	if s[0].Account != "0012345678" || s[0].Name != "สมชาย" || s[0].Amount != 120.5 || s[0].Count != 7 || *s[0].Ref != "AB12" || s[0].Status != 1 {
+		t.Error("ParseFixed error", s[0])
+	}
+	if s[1].Amount != -0.25 || s[1].Count != 12 || *s[1].Ref != "" {
+		t.Error("ParseFixed error", s[1])
+	}
+}
+
+func TestParseFixedErrors(t *testing.T) {
+	// Given
+	data := []byte("0012345678N1      00000abcd  7     Active\nshort\n")
+
+	// When
+	_, err := csvx.ParseFixed[Transfer](data, func(o *csvx.FixedOptions) {
+		o.Strict = true
+	})
+
+	// Then
+	var errs csvx.ParseErrors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatal("ParseFixed error", err)
+	}
+	if errs[0].Row != 0 || errs[0].Field != "Amount" || errs[0].Value != "abcd" {
+		t.Error("ParseFixed error", errs[0])
+	}
+	if errs[1].Row != 1 || !errors.Is(errs[1], csvx.ErrRecordLength) {
+		t.Error("ParseFixed error", errs[1])
+	}
+}
+
+func TestConvertFixed(t *testing.T) {
+	// Given
+	ref := "AB12"
+	m := []Transfer{
+		{Account: "0012345678", Name: "สมชาย", Amount: 120.5, Count: 7, Ref: &ref, Status: 1},
+		{Account: "9876543210", Name: "Jane", Amount: -0.25, Count: 12, Status: 1},
+	}
+	expected := "0012345678สมชาย   0000120.5  7 AB12Active\r\n" +
+		"9876543210Jane    -00000.25 12 NONEActive\r\n"
+
+	// When
+	result, err := csvx.ConvertFixed(m, func(o *csvx.FixedOptions) {
+		o.LineTerminator = "\r\n"
+	})
+
+	// Then
+	if err != nil || result != expected {
+		t.Errorf("ConvertFixed error: %q %v", result, err)
+	}
+}
+
+func TestConvertFixedTooLong(t *testing.T) {
+	// Given
+	m := []Transfer{{Account: "12345678901"}}
+
+	// When
+	_, err := csvx.ConvertFixed(m)
+
+	// Then
+	if err == nil || err.Error() != `row 0, field Account: value "12345678901" is longer than 10 characters` {
+		t.Error("ConvertFixed error", err)
+	}
+}
+
+func TestConvertFixedOverlap(t *testing.T) {
+	// Given
+	type Overlap struct {
+		A string `pos:"1" width:"5"`
+		B string `pos:"3" width:"5"`
+	}
+
+	// When
+	_, err := csvx.ConvertFixed([]Overlap{{}})
+
+	// Then
+	if err == nil || err.Error() != "field B overlaps field A" {
+		t.Error("ConvertFixed error", err)
+	}
+}
+
+func TestFixedFieldsAreNotCSVColumns(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"Account", "Name"},
+		{"0012345678", "N1"},
+	}
+
+	// When
+	s := csvx.Parser[Transfer](rows)
+	err := csvx.ValidateHeader[Transfer](rows[0], func(o *csvx.ParserOptions) {
+		o.RejectUnknownColumns = true
+	})
+
+	// Then
+	if s[0].Account != "" || s[0].Name != "N1" {
+		t.Error("Fields without a header tag must not be parsed from csv", s[0])
+	}
+	var headerError *csvx.HeaderError
+	if !errors.As(err, &headerError) || len(headerError.Unexpected) != 1 || headerError.Unexpected[0] != "Account" {
+		t.Error("Fields without a header tag must not be csv columns", err)
+	}
+}
@@ -0,0 +1,101 @@
+package csvx
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// formulaPrefixes are the characters that make a spreadsheet evaluate a cell as a formula, following the
+// OWASP guidance on CSV injection.
+const formulaPrefixes = "=+-@\t\r"
+
+// startsFormula reports whether the cell starts with a character of formulaPrefixes.
+func startsFormula(value string) bool {
+	return value != "" && strings.IndexByte(formulaPrefixes, value[0]) >= 0
+}
+
+// isNumeric reports whether the cell is a plain number such as -12.5, that a spreadsheet does not evaluate.
+func isNumeric(value string) bool {
+	_, err := strconv.ParseFloat(value, 64)
+	return err == nil && !strings.ContainsAny(value, "xXnN")
+}
+
+// IsFormula reports whether a spreadsheet would evaluate the cell as a formula: it starts with
+// '=', '+', '-', '@', a tab or a carriage return and is not a number such as -12.5.
+func IsFormula(value string) bool {
+	return startsFormula(value) && !isNumeric(value)
+}
+
+// sanitizeFormula prefixes the cell with a single quote when it starts with a formula character,
+// so that a spreadsheet displays it as text. Numbers are kept when allowNumbers is true.
+func sanitizeFormula(value string, allowNumbers bool) string {
+	if !startsFormula(value) || (allowNumbers && isNumeric(value)) {
+		return value
+	}
+	return "'" + value
+}
+
+// sanitize returns the cell written for the column, see ConvertOptions.AllowFormulas.
+// The `formula:"allow"` tag writes the column verbatim and the `formula:"number"` tag keeps its numbers,
+// which is the default for the numeric fields. The cells without a column keep their numbers, like IsFormula.
+func (o *ConvertOptions) sanitize(value string, c *column) string {
+	if o.AllowFormulas {
+		return value
+	}
+	if c == nil {
+		return sanitizeFormula(value, true)
+	}
+	switch c.formula {
+	case "allow":
+		return value
+	case "number":
+		return sanitizeFormula(value, true)
+	}
+	return sanitizeFormula(value, c.numeric())
+}
+
+// numeric reports whether the values of the column are numbers.
+func (c *column) numeric() bool {
+	t := indirectType(c.scalarType())
+	switch t {
+	case decimalType, bigIntType, bigRatType, bigFloatType:
+		return c.enumOf() == nil
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return c.enumOf() == nil
+	}
+	return false
+}
+
+// FormulaCell is a cell that a spreadsheet would evaluate as a formula, as found by FindFormulas.
+type FormulaCell struct {
+	// Row is the index of the record in the rows.
+	Row int
+	// Column is the index of the cell in the record.
+	Column int
+	// Value is the text of the cell.
+	Value string
+}
+
+// FindFormulas returns the cells of rows that a spreadsheet would evaluate as a formula, see IsFormula,
+// to flag uploaded files before they are stored or exported again.
+//
+//	rows := csvx.ByteReader(data)
+//	if cells := csvx.FindFormulas(rows); len(cells) > 0 {
+//		return fmt.Errorf("row %d: suspicious cell %q", cells[0].Row, cells[0].Value)
+//	}
+func FindFormulas(rows [][]string) []FormulaCell {
+	var cells []FormulaCell
+	for i, row := range rows {
+		for j, value := range row {
+			if IsFormula(value) {
+				cells = append(cells, FormulaCell{Row: i, Column: j, Value: value})
+			}
+		}
+	}
+	return cells
+}
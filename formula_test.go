@@ -0,0 +1,120 @@
+package csvx_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+type Payment struct {
+	Name    string  `header:"Name" no:"1"`
+	Amount  float64 `header:"Amount" no:"2"`
+	Balance string  `header:"Balance" no:"3" formula:"number"`
+	Note    string  `header:"Note" no:"4"`
+	Script  string  `header:"Script" no:"5" formula:"allow"`
+}
+
+func TestConvertWithOptionsSanitizesFormulas(t *testing.T) {
+	// Given
+	m := []Payment{{Name: "=HYPERLINK(\"http://x\")", Amount: -12.5, Balance: "-3", Note: "-3", Script: "=1+1"}}
+	expected := csvx.Utf8BOM + "Name,Amount,Balance,Note,Script\n'=HYPERLINK(\"http://x\"),-12.5,-3,'-3,=1+1"
+
+	// When
+	result, err := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+		o.IgnoreDoubleQuote = true
+	})
+
+	// Then
+	if err != nil || result != expected {
+		t.Errorf("Convert error: %q %v", result, err)
+	}
+}
+
+func TestConvertWithOptionsAllowFormulas(t *testing.T) {
+	// Given
+	m := []Payment{{Name: "+cmd|'/C calc'!A0"}}
+
+	// When
+	result, _ := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+		o.IgnoreDoubleQuote = true
+		o.AllowFormulas = true
+	})
+
+	// Then
+	if result != csvx.Utf8BOM+"Name,Amount,Balance,Note,Script\n+cmd|'/C calc'!A0,0,,," {
+		t.Errorf("Convert error: %q", result)
+	}
+}
+
+func TestManualConvertWithOptionsSanitizesFormulas(t *testing.T) {
+	// Given
+	m := []Payment{{Name: "@SUM(A1:A2)", Amount: -1, Note: "\tx"}}
+
+	// When
+	result, _ := csvx.ManualConvertWithOptions(m, []string{"Name", "Amount", "Note"}, func(data Payment) []string {
+		return []string{data.Name, csvx.F64ToString(data.Amount), data.Note}
+	})
+
+	// Then
+	if result != csvx.Utf8BOM+"Name,Amount,Note\n'@SUM(A1:A2),-1,'\tx\n" {
+		t.Errorf("ManualConvert error: %q", result)
+	}
+}
+
+func TestIsFormula(t *testing.T) {
+	tests := map[string]bool{
+		"=1+1":    true,
+		"+cmd":    true,
+		"-2+3":    true,
+		"@SUM(1)": true,
+		"\t=1":    true,
+		"\r=1":    true,
+		"-12.5":   false,
+		"+1":      false,
+		"-Inf":    true,
+		"Name":    false,
+		"":        false,
+	}
+	for value, expected := range tests {
+		if csvx.IsFormula(value) != expected {
+			t.Errorf("IsFormula(%q) != %v", value, expected)
+		}
+	}
+}
+
+func TestFindFormulas(t *testing.T) {
+	// Given
+	rows := [][]string{{"Name", "Amount"}, {"N1", "-12"}, {"=HYPERLINK(\"x\")", "1"}}
+
+	// When
+	cells := csvx.FindFormulas(rows)
+
+	// Then
+	expected := []csvx.FormulaCell{{Row: 2, Column: 0, Value: "=HYPERLINK(\"x\")"}}
+	if !reflect.DeepEqual(cells, expected) {
+		t.Error("FindFormulas error", cells)
+	}
+}
+
+func TestConvertKeepsFormulas(t *testing.T) {
+	// Given
+	m := []MyStruct{{ID: 1, Name: "-foo"}, {ID: 2, Name: "=1+1"}, {ID: 3, Name: "-5"}, {ID: 4, Name: "@SUM(A1)"}}
+	expected := csvx.Utf8BOM + `"ID","Name Space"
+"1","-foo"
+"2","=1+1"
+"3","-5"
+"4","@SUM(A1)"`
+
+	// When
+	result := csvx.Convert(m)
+	resultIgnoreDoubleQuote := csvx.Convert(m, true)
+
+	// Then
+	if result != expected {
+		t.Errorf("Convert error: %q", result)
+	}
+	if resultIgnoreDoubleQuote != csvx.Utf8BOM+"ID,Name Space\n1,-foo\n2,=1+1\n3,-5\n4,@SUM(A1)" {
+		t.Errorf("Convert error: %q", resultIgnoreDoubleQuote)
+	}
+}
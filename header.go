@@ -0,0 +1,493 @@
+package csvx
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// target is the column of the struct that a csv column is mapped to.
+// For a repeated column group, item is the position of the csv column within the group.
+type target struct {
+	col  *column
+	item int
+	key  string
+}
+
+// frame is the mapping of the columns of a csv to the struct, resolved from ParserOptions.
+type frame struct {
+	// targets is the struct column of each csv column, nil for unmapped columns.
+	targets []*target
+	// columns is the name of each csv column, for errors.
+	columns []string
+	// start is the index of the first data row and end the index after the last one.
+	start int
+	end   int
+}
+
+// newFrame maps the columns of rows to the struct type t, validating the header row if requested.
+// The rows before the header and the trailer rows are stored in ParserOptions.Metadata.
+func newFrame(t reflect.Type, rows [][]string, o *ParserOptions) (*frame, error) {
+	cols := columnsOf(t)
+	f := &frame{}
+	if o.Metadata != nil {
+		*o.Metadata = Metadata{}
+	}
+	if o.HeaderRow < 0 {
+		return nil, fmt.Errorf("header row %d is out of range", o.HeaderRow)
+	}
+	if o.TrailerRows < 0 {
+		return nil, fmt.Errorf("trailer rows %d is negative", o.TrailerRows)
+	}
+	if o.Headerless {
+		f.start = o.HeaderRow
+		if f.start > len(rows) {
+			f.start = len(rows)
+		}
+
+		// A repeated column group without a size takes the trailing columns of the rows
+		cells := exportCells(reflect.MakeSlice(reflect.SliceOf(t), 0, 0), nil)
+		var group *column
+		exported := exportColumns(t)
+		for k, c := range exported {
+			if c.pattern != nil && c.max == 0 && c.field.Type.Kind() == reflect.Slice {
+				if k < len(exported)-1 {
+					return nil, fmt.Errorf("repeated column group %s needs a max tag when it is not the last column of a headerless file", c.name)
+				}
+				group = c
+				cells = append(cells, cell{col: c, item: 0, header: c.header})
+			}
+		}
+		positions, width := cellPositions(cells)
+		start := width
+		if group != nil {
+			start = positions[len(cells)-1]
+			cells = cells[:len(cells)-1]
+			width = start
+			for _, row := range rows[f.start:] {
+				if len(row) > width {
+					width = len(row)
+				}
+			}
+		}
+
+		f.targets = make([]*target, width)
+		f.columns = make([]string, width)
+		for i, e := range cells {
+			f.targets[positions[i]] = &target{col: e.col, item: e.item}
+			f.columns[positions[i]] = e.header
+		}
+		for j := start; j < width; j++ {
+			f.targets[j] = &target{col: group, item: j - start}
+			f.columns[j] = strings.Replace(group.header, "*", strconv.Itoa(j-start+1), 1)
+		}
+	} else {
+		if err := o.Mapping.validate(cols); err != nil {
+			return nil, err
+		}
+		index, err := findHeader(cols, rows, o)
+		if err != nil {
+			return nil, err
+		}
+		header := rows[index]
+		f.targets = matchHeader(cols, header, o)
+		f.start = index + 1
+		for _, head := range header {
+			f.columns = append(f.columns, RemoveDoubleQuote(head))
+		}
+		if o.validatesHeader() {
+			if err := checkHeader(cols, header, f.targets, o); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Drop the trailer rows
+	f.end = len(rows) - o.TrailerRows
+	if f.end < f.start {
+		f.end = f.start
+	}
+	for o.IsTrailer != nil && f.end > f.start && o.IsTrailer(rows[f.end-1]) {
+		f.end--
+	}
+
+	if o.Metadata != nil {
+		o.Metadata.HeaderRow = -1
+		o.Metadata.Preamble = rows[:f.start]
+		o.Metadata.Trailer = rows[f.end:]
+		if !o.Headerless {
+			o.Metadata.HeaderRow = f.start - 1
+			o.Metadata.Preamble = rows[:f.start-1]
+		}
+	}
+	return f, nil
+}
+
+// findHeader returns the index of the header row: ParserOptions.HeaderRow, or with FindHeader the first row
+// from there that contains every required column and at least one column of the struct.
+func findHeader(cols []*column, rows [][]string, o *ParserOptions) (int, error) {
+	if o.HeaderRow < 0 || o.HeaderRow >= len(rows) {
+		return 0, fmt.Errorf("header row %d is out of range", o.HeaderRow)
+	}
+	if o.DetectHeader > 0 {
+		index, confidence := detectHeader(cols, rows[o.HeaderRow:], o.DetectHeader, o)
+		if index < 0 {
+			return 0, errors.New("header row not found")
+		}
+		if o.Metadata != nil {
+			o.Metadata.HeaderConfidence = confidence
+		}
+		return o.HeaderRow + index, nil
+	}
+	if !o.FindHeader {
+		return o.HeaderRow, nil
+	}
+
+	for i := o.HeaderRow; i < len(rows); i++ {
+		found := map[*column]bool{}
+		for _, t := range matchHeader(cols, rows[i], o) {
+			if t != nil && !t.col.rest {
+				found[t.col] = true
+			}
+		}
+		complete := len(found) > 0
+		for _, c := range cols {
+			if c.required && !found[c] {
+				complete = false
+			}
+		}
+		if complete {
+			return i, nil
+		}
+	}
+	return 0, errors.New("header row not found")
+}
+
+// detectHeader returns the index of the row among the first maxScan rows whose cells best match cols,
+// and its score from 0 to 1. The score weighs the struct columns found in the row against the non-empty
+// cells of the row, so a data row that contains one header-like value scores low.
+func detectHeader(cols []*column, rows [][]string, maxScan int, o *ParserOptions) (int, float64) {
+	total := 0
+	for _, c := range cols {
+		if !c.rest {
+			total++
+		}
+	}
+
+	best, bestScore := -1, 0.0
+	for i := 0; i < len(rows) && i < maxScan; i++ {
+		cells := 0
+		matched := 0
+		found := map[*column]bool{}
+		for j, t := range matchHeader(cols, rows[i], o) {
+			if RemoveDoubleQuote(rows[i][j]) == "" {
+				continue
+			}
+			cells++
+			if t != nil && !t.col.rest {
+				matched++
+				found[t.col] = true
+			}
+		}
+		if cells == 0 || total == 0 {
+			continue
+		}
+		score := float64(len(found)+matched) / float64(total+cells)
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best, bestScore
+}
+
+// DetectHeader scans the first maxScan rows and returns the index of the row whose cells best match
+// the `header` tags of T, with a confidence from 0 to 1. It returns -1 if no row matches any header.
+//
+//	index, confidence := csvx.DetectHeader[Struct](rows, 20)
+func DetectHeader[T any](rows [][]string, maxScan int, options ...func(o *ParserOptions)) (int, float64) {
+	return detectHeader(columnsOf(reflect.TypeOf(model[T]{}.Data)), rows, maxScan, parserOptions(options))
+}
+
+// matchHeader maps each cell of the header row to a column of cols, or to nil if no column matches.
+// ParserOptions.Mapping takes precedence over the tags, then an exact header match takes precedence
+// over a repeated column group, and the remaining headers are mapped to the rest column if the struct has one.
+func matchHeader(cols []*column, header []string, o *ParserOptions) []*target {
+	targets := make([]*target, len(header))
+	items := map[*column]int{}
+
+	// Columns of the mapping are only matched through the mapping
+	mapped := map[string]*column{}
+	skip := map[*column]bool{}
+	for head, field := range o.Mapping {
+		if c := o.Mapping.column(cols, field); c != nil {
+			mapped[o.normalize(head)] = c
+			skip[c] = true
+		}
+	}
+
+	for j := range header {
+		head := RemoveDoubleQuote(header[j])
+		if c, ok := mapped[o.normalize(head)]; ok {
+			targets[j] = &target{col: c, item: -1}
+			if c.pattern != nil {
+				targets[j].item = items[c]
+				items[c]++
+			}
+			continue
+		}
+		for _, c := range cols {
+			if !skip[c] && c.pattern == nil && !c.rest && c.matches(head, o) {
+				targets[j] = &target{col: c, item: -1}
+				break
+			}
+		}
+		if targets[j] != nil {
+			continue
+		}
+		for _, c := range cols {
+			if !skip[c] && c.pattern != nil && c.pattern.MatchString(head) {
+				targets[j] = &target{col: c, item: items[c]}
+				items[c]++
+				break
+			}
+		}
+		if targets[j] != nil {
+			continue
+		}
+		for _, c := range cols {
+			if c.rest {
+				targets[j] = &target{col: c, item: -1, key: head}
+				break
+			}
+		}
+	}
+	return targets
+}
+
+// matches reports whether head is one of the names of the column, after ParserOptions.HeaderNormalizer.
+func (c *column) matches(head string, o *ParserOptions) bool {
+	head = o.normalize(head)
+	for _, name := range c.names {
+		if o.normalize(name) == head {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize applies ParserOptions.HeaderNormalizer to header, if any.
+func (o *ParserOptions) normalize(header string) string {
+	if o.HeaderNormalizer != nil {
+		return o.HeaderNormalizer(header)
+	}
+	return header
+}
+
+// NormalizeHeader returns header in a form that ignores formatting differences between vendors: letters are
+// lower-cased and zero-width characters, punctuation and whitespace are removed. "Customer ID", "customer_id",
+// "Customer-ID" and "CustomerID " all give "customerid", "E-mail" and "Email" give "email".
+//
+//	s := csvx.Parser[Struct](rows, func(o *csvx.ParserOptions) {
+//		o.HeaderNormalizer = csvx.NormalizeHeader
+//	})
+func NormalizeHeader(header string) string {
+	var b strings.Builder
+	for _, r := range header {
+		// Zero-width characters are format characters, not letters
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) {
+			b.WriteRune(unicode.ToLower(r))
+		}
+	}
+	return b.String()
+}
+
+// parse converts the text of a cell into the struct value v.
+func (t *target) parse(v reflect.Value, text string, o *ParserOptions) error {
+	c := t.col
+	if _, ok := c.valueOf(v); !ok && text == "" {
+		// Keep nested struct pointers nil for empty cells
+		return nil
+	}
+
+	field, err := c.fieldOf(v)
+	if err != nil {
+		return err
+	}
+	if c.rest {
+		if c.err != nil {
+			return c.err
+		}
+		if field.IsNil() {
+			field.Set(reflect.MakeMap(field.Type()))
+		}
+		field.SetMapIndex(reflect.ValueOf(t.key).Convert(field.Type().Key()), reflect.ValueOf(text).Convert(field.Type().Elem()))
+		return nil
+	}
+	if t.item < 0 {
+		return c.parseValue(field, text, o)
+	}
+	if c.err != nil {
+		return c.err
+	}
+	if text == "" {
+		return nil
+	}
+
+	// Repeated column group
+	if field.Kind() == reflect.Array {
+		if t.item >= field.Len() {
+			return fmt.Errorf("column %d of the group does not fit in %s", t.item+1, field.Type())
+		}
+		return c.parseItem(field.Index(t.item), text, o)
+	}
+	// Slices grow to the item, the empty cells before it being zero values
+	if t.item >= field.Len() {
+		field.Set(reflect.AppendSlice(field, reflect.MakeSlice(field.Type(), t.item+1-field.Len(), t.item+1-field.Len())))
+	}
+	return c.parseItem(field.Index(t.item), text, o)
+}
+
+// checkHeader returns a *HeaderError listing the required columns missing from header, the duplicated
+// columns and, with RejectUnknownColumns, the columns not mapped to cols. The reported columns that look
+// like a typo are given a suggestion.
+func checkHeader(cols []*column, header []string, targets []*target, o *ParserOptions) error {
+	e := &HeaderError{}
+
+	found := map[*column]bool{}
+	seen := map[string]bool{}
+	duplicate := map[string]bool{}
+	var unknown []string
+	for j := range header {
+		head := RemoveDoubleQuote(header[j])
+		if head == "" {
+			// Spreadsheets export trailing empty columns
+			continue
+		}
+
+		// Two columns are duplicated when they have the same name or when they set the same field
+		key := o.normalize(head)
+		t := targets[j]
+		if (seen[key] || (t != nil && t.item < 0 && !t.col.rest && found[t.col])) && !duplicate[key] {
+			duplicate[key] = true
+			e.Duplicate = append(e.Duplicate, head)
+		}
+		seen[key] = true
+		if t == nil {
+			unknown = append(unknown, head)
+			continue
+		}
+		found[t.col] = true
+	}
+
+	if o.RejectUnknownColumns {
+		e.Unexpected = unknown
+	}
+	missing := map[string]bool{}
+	for _, c := range cols {
+		if c.required && !found[c] {
+			e.Missing = append(e.Missing, c.header)
+			missing[c.header] = true
+		}
+	}
+
+	// Suggestions only explain the columns already reported
+	for head, suggestion := range suggestHeaders(cols, found, unknown, o) {
+		if o.RejectUnknownColumns || missing[suggestion] {
+			if e.Suggestions == nil {
+				e.Suggestions = map[string]string{}
+			}
+			e.Suggestions[head] = suggestion
+		}
+	}
+
+	if len(e.Missing) > 0 || len(e.Unexpected) > 0 || len(e.Duplicate) > 0 {
+		return e
+	}
+	return nil
+}
+
+// suggestHeaders returns the closest unmapped struct header for each unknown header, when the
+// edit distance between them is at most a third of their length.
+func suggestHeaders(cols []*column, found map[*column]bool, unknown []string, o *ParserOptions) map[string]string {
+	suggestions := map[string]string{}
+	for _, head := range unknown {
+		best := -1
+		for _, c := range cols {
+			if found[c] || c.rest || c.pattern != nil {
+				continue
+			}
+			for _, name := range c.names {
+				a, b := strings.ToLower(head), strings.ToLower(name)
+				if o.HeaderNormalizer != nil {
+					a, b = o.HeaderNormalizer(head), o.HeaderNormalizer(name)
+				}
+				d := editDistance(a, b)
+				size := utf8.RuneCountInString(a)
+				if n := utf8.RuneCountInString(b); n > size {
+					size = n
+				}
+				if d*3 <= size && (best < 0 || d < best) {
+					best = d
+					suggestions[head] = c.header
+				}
+			}
+		}
+	}
+	return suggestions
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions
+// of adjacent characters needed to turn a into b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = minInt(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = minInt(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
+
+// ValidateHeader checks the header row of a csv against the struct T without parsing any data.
+// It returns a *HeaderError listing the missing, unexpected and duplicate columns, or nil if the header is valid.
+//
+//	err := csvx.ValidateHeader[Struct](rows[0], func(o *csvx.ParserOptions) {
+//		o.RejectUnknownColumns = true
+//	})
+func ValidateHeader[T any](header []string, options ...func(o *ParserOptions)) error {
+	o := parserOptions(options)
+	cols := columnsOf(reflect.TypeOf(model[T]{}.Data))
+	if err := o.Mapping.validate(cols); err != nil {
+		return err
+	}
+	return checkHeader(cols, header, matchHeader(cols, header, o), o)
+}
@@ -0,0 +1,463 @@
+package csvx_test
+
+import (
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+type StructGroup struct {
+	ID     int      `header:"ID" no:"1"`
+	Phones []string `header:"Phone *" no:"2"`
+	Emails []string `header:"Email *" no:"3" max:"2"`
+	Scores [2]int   `header:"Score*" pattern:"^Score ?[0-9]+$" no:"4"`
+}
+
+func TestParserGroup(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"Phone 2", "ID", "Phone 1", "Email 1", "Score1", "Score 2", "Phone 3"},
+		{"02", "1", "01", "a@b.c", "7", "8", ""},
+		{"", "2", "", "", "", "", "03"},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructGroup](rows)
+
+	// Then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s[0].Phones, []string{"02", "01"}) || s[0].Scores != [2]int{7, 8} || s[0].Emails[0] != "a@b.c" {
+		t.Error("Parse group error", s[0])
+	}
+	if !reflect.DeepEqual(s[1].Phones, []string{"", "", "03"}) || s[1].Emails != nil {
+		t.Error("Parse group with empty cells error", s[1])
+	}
+}
+
+func TestParserGroupGap(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"ID", "Phone 1", "Phone 2", "Phone 3", "Email 1", "Email 2"},
+		{"1", "", "02", "", "", "b@c.d"},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructGroup](rows)
+
+	// Then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s[0].Phones, []string{"", "02"}) || !reflect.DeepEqual(s[0].Emails, []string{"", "b@c.d"}) {
+		t.Error("Parse group with a gap error", s[0])
+	}
+}
+
+func TestConvertGroup(t *testing.T) {
+	// Given
+	m := []StructGroup{
+		{ID: 1, Phones: []string{"01"}, Emails: []string{"a", "b", "c"}},
+		{ID: 2, Phones: []string{"01", "02", "03"}, Scores: [2]int{1, 2}},
+	}
+	expected := csvx.Utf8BOM + `"ID","Phone 1","Phone 2","Phone 3","Email 1","Email 2","Score1","Score2"
+"1","01","","","a","b","0","0"
+"2","01","02","03","","","1","2"`
+
+	// When
+	result := csvx.Convert(m)
+
+	// Then
+	if result != expected {
+		t.Error("Convert group error:\n", result)
+	}
+}
+
+type StructRest struct {
+	ID    int               `header:"ID" no:"1"`
+	Name  string            `header:"Name" no:"2"`
+	Extra map[string]string `header:",rest"`
+}
+
+func TestParserRest(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"Color", "ID", "Name", "Size"},
+		{"red", "1", "N1", "L"},
+		{"", "2", "N2", "S"},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructRest](rows)
+
+	// Then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s[0].Extra, map[string]string{"Color": "red", "Size": "L"}) {
+		t.Error("Parse rest error", s[0])
+	}
+	if s[1].Name != "N2" || s[1].Extra["Size"] != "S" {
+		t.Error("Parse rest error", s[1])
+	}
+}
+
+func TestConvertRest(t *testing.T) {
+	// Given
+	m := []StructRest{
+		{ID: 1, Name: "N1", Extra: map[string]string{"Size": "L"}},
+		{ID: 2, Name: "N2", Extra: map[string]string{"Color": "red"}},
+		{ID: 3, Name: "N3"},
+	}
+	expected := csvx.Utf8BOM + `"ID","Name","Color","Size"
+"1","N1","","L"
+"2","N2","red",""
+"3","N3","",""`
+
+	// When
+	result := csvx.Convert(m)
+
+	// Then
+	if result != expected {
+		t.Error("Convert rest error:\n", result)
+	}
+}
+
+type StructRequired struct {
+	ID    int    `header:"ID,required"`
+	Name  string `header:"Name,required"`
+	Email string `header:"Email"`
+}
+
+func TestValidateHeader(t *testing.T) {
+	// Given
+	header := []string{"ID", "Email", "Phone", "Email"}
+
+	// When
+	err := csvx.ValidateHeader[StructRequired](header)
+	strictErr := csvx.ValidateHeader[StructRequired](header, func(o *csvx.ParserOptions) {
+		o.RejectUnknownColumns = true
+	})
+	validErr := csvx.ValidateHeader[StructRequired]([]string{"Name", "ID"})
+
+	// Then
+	var headerError *csvx.HeaderError
+	if !errors.As(err, &headerError) || !reflect.DeepEqual(headerError.Missing, []string{"Name"}) ||
+		!reflect.DeepEqual(headerError.Duplicate, []string{"Email"}) || headerError.Unexpected != nil {
+		t.Error("Validate header error:", err)
+	}
+	if !errors.As(strictErr, &headerError) || !reflect.DeepEqual(headerError.Unexpected, []string{"Phone"}) {
+		t.Error("Validate unknown columns error:", strictErr)
+	}
+	if validErr != nil {
+		t.Error("Valid header error:", validErr)
+	}
+}
+
+func TestValidateHeaderEmptyColumns(t *testing.T) {
+	// Given
+	header := []string{"ID", "Name", "", ""}
+
+	// When
+	err := csvx.ValidateHeader[StructRequired](header)
+	strictErr := csvx.ValidateHeader[StructRequired](header, func(o *csvx.ParserOptions) {
+		o.RejectUnknownColumns = true
+	})
+
+	// Then
+	if err != nil || strictErr != nil {
+		t.Error("Empty columns must be ignored:", err, strictErr)
+	}
+}
+
+type StructAliasRequired struct {
+	CustomerID string `header:"Customer ID|customer_id"`
+}
+
+func TestValidateHeaderDuplicateAlias(t *testing.T) {
+	// Given
+	header := []string{"Customer ID", "customer_id"}
+
+	// When
+	err := csvx.ValidateHeader[StructAliasRequired](header)
+
+	// Then
+	var headerError *csvx.HeaderError
+	if !errors.As(err, &headerError) || !reflect.DeepEqual(headerError.Duplicate, []string{"customer_id"}) {
+		t.Error("Aliases of the same field must be duplicated:", err)
+	}
+}
+
+func TestParserValidateHeader(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"ID", "Email"},
+		{"1", "a@b.c"},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructRequired](rows, func(o *csvx.ParserOptions) {
+		o.ValidateHeader = true
+	})
+	ss := csvx.ParserString[StructRequired](rows, func(o *csvx.ParserOptions) {
+		o.ValidateHeader = true
+	})
+
+	// Then
+	if err == nil || err.Error() != `invalid header: missing column "Name"` || s != nil || ss != nil {
+		t.Error("Parse must stop on invalid header:", err)
+	}
+}
+
+func TestTryParserByReaderValidateHeader(t *testing.T) {
+	// Given
+	invalid := csv.NewReader(strings.NewReader("ID;Email\n1;a@b.c\n"))
+	invalid.Comma = ';'
+	valid := csv.NewReader(strings.NewReader("ID;Name\n1;N1\n"))
+	valid.Comma = ';'
+	validate := func(o *csvx.ParserOptions) {
+		o.ValidateHeader = true
+	}
+
+	// When
+	_, err := csvx.TryParserByReader[StructRequired](invalid, validate)
+	s, vErr := csvx.TryParserByReader[StructRequired](valid, validate)
+
+	// Then
+	var headerError *csvx.HeaderError
+	if !errors.As(err, &headerError) || !reflect.DeepEqual(headerError.Missing, []string{"Name"}) {
+		t.Error("Parse by reader must validate the header:", err)
+	}
+	if vErr != nil || len(s) != 1 || s[0].Name != "N1" {
+		t.Error("Parse by reader error", s, vErr)
+	}
+}
+
+type StructAlias struct {
+	CustomerID string `header:"Customer ID|CustID" no:"1"`
+	Name       string `header:"Name" no:"2"`
+}
+
+func TestParserAlias(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"CustID", "Name"},
+		{"C1", "N1"},
+	}
+	expected := csvx.Utf8BOM + `"Customer ID","Name"
+"C1","N1"`
+
+	// When
+	s := csvx.Parser[StructAlias](rows)
+	c := csvx.Convert(s)
+
+	// Then
+	if s[0].CustomerID != "C1" || c != expected {
+		t.Error("Parse alias error", s, c)
+	}
+}
+
+func TestParserHeaderNormalizer(t *testing.T) {
+	for _, head := range []string{"customer_id", "CustomerID ", "CUSTOMER-ID", "Customer-ID", "customer.id", "Customer\u200b  ID", "cust_id"} {
+		// Given
+		rows := [][]string{
+			{head, "NAME"},
+			{"C1", "N1"},
+		}
+
+		// When
+		exact := csvx.Parser[StructAlias](rows)
+		s := csvx.Parser[StructAlias](rows, func(o *csvx.ParserOptions) {
+			o.HeaderNormalizer = csvx.NormalizeHeader
+		})
+
+		// Then
+		if exact[0].CustomerID != "" {
+			t.Error("Normalizer must be opt-in", head)
+		}
+		if s[0].CustomerID != "C1" || s[0].Name != "N1" {
+			t.Error("Parse normalized header error", head, s)
+		}
+	}
+}
+
+func TestNormalizeHeader(t *testing.T) {
+	for header, expected := range map[string]string{
+		" Order\tNo. (THB) ": "ordernothb",
+		"E-mail":             "email",
+		"Email":              "email",
+		"e-Mail":             "email",
+		"CUSTOMER-ID":        "customerid",
+		"Unit_Price (฿)":     "unitprice",
+		"ชื่อ ลูกค้า":        "ชื่อลูกค้า",
+	} {
+		if h := csvx.NormalizeHeader(header); h != expected {
+			t.Error("Normalize header error", header, h)
+		}
+	}
+}
+
+func TestValidateHeaderSuggestion(t *testing.T) {
+	// Given
+	header := []string{"ID", "Name", "Emial", "Comment"}
+
+	// When
+	err := csvx.ValidateHeader[StructRequired](header, func(o *csvx.ParserOptions) {
+		o.RejectUnknownColumns = true
+	})
+
+	// Then
+	var headerError *csvx.HeaderError
+	if !errors.As(err, &headerError) || headerError.Suggestions["Emial"] != "Email" {
+		t.Fatal("Suggestion error:", err)
+	}
+	if err.Error() != `invalid header: unknown column "Emial" (did you mean "Email"?); unknown column "Comment"` {
+		t.Error("Suggestion message error:", err)
+	}
+}
+
+func TestValidateHeaderAcceptsExtraColumns(t *testing.T) {
+	// Given
+	header := []string{"ID", "Name", "Mail", "Emial"}
+
+	// When
+	err := csvx.ValidateHeader[StructRequired](header)
+
+	// Then
+	if err != nil {
+		t.Error("Extra columns must be accepted:", err)
+	}
+}
+
+func TestValidateHeaderMissingSuggestion(t *testing.T) {
+	// Given
+	header := []string{"ID", "Nmae"}
+
+	// When
+	err := csvx.ValidateHeader[StructRequired](header)
+
+	// Then
+	var headerError *csvx.HeaderError
+	if !errors.As(err, &headerError) || len(headerError.Unexpected) != 0 {
+		t.Fatal("Missing suggestion error:", err)
+	}
+	if err.Error() != `invalid header: missing column "Name" (did you mean "Nmae"?)` {
+		t.Error("Missing suggestion message error:", err)
+	}
+}
+
+func TestParserPreambleAndTrailer(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"Report generated 2026-01-01"},
+		{"Branch", "Bangkok"},
+		{"ID", "Name", "Email"},
+		{"1", "N1", "a@b.c"},
+		{"2", "N2", "d@e.f"},
+		{"Total", "2"},
+		{"EOF"},
+	}
+	var meta csvx.Metadata
+
+	// When
+	s, err := csvx.TryParser[StructRequired](rows, func(o *csvx.ParserOptions) {
+		o.FindHeader = true
+		o.TrailerRows = 1
+		o.IsTrailer = func(row []string) bool {
+			return row[0] == "Total"
+		}
+		o.Metadata = &meta
+	})
+
+	// Then
+	if err != nil || len(s) != 2 || s[1].Email != "d@e.f" {
+		t.Fatal("Parse with preamble error", s, err)
+	}
+	if meta.HeaderRow != 2 || len(meta.Preamble) != 2 || !reflect.DeepEqual(meta.Trailer, rows[5:]) {
+		t.Error("Metadata error", meta)
+	}
+}
+
+func TestParserHeaderRow(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"Title"},
+		{"ID", "Name"},
+		{"1", "N1"},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructRequired](rows, func(o *csvx.ParserOptions) {
+		o.HeaderRow = 1
+	})
+	_, nErr := csvx.TryParser[StructRequired](rows[:1], func(o *csvx.ParserOptions) {
+		o.FindHeader = true
+	})
+
+	// Then
+	if err != nil || s[0].Name != "N1" {
+		t.Error("Parse header row error", s, err)
+	}
+	if nErr == nil {
+		t.Error("Missing header row must be an error")
+	}
+}
+
+func TestParserInvalidRows(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"ID", "Name"},
+		{"1", "N1"},
+	}
+
+	// When
+	_, headerErr := csvx.TryParser[StructRequired](rows, func(o *csvx.ParserOptions) {
+		o.Headerless = true
+		o.HeaderRow = -1
+	})
+	_, trailerErr := csvx.TryParser[StructRequired](rows, func(o *csvx.ParserOptions) {
+		o.TrailerRows = -1
+	})
+	s := csvx.ParserString[StructRequired](rows, func(o *csvx.ParserOptions) {
+		o.Headerless = true
+		o.TrailerRows = -1
+	})
+
+	// Then
+	if headerErr == nil || trailerErr == nil || s != nil {
+		t.Error("Negative rows must be an error", headerErr, trailerErr, s)
+	}
+}
+
+func TestDetectHeader(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"Monthly export", "", ""},
+		{"ID", "", ""},
+		{"", "", ""},
+		{"ID", "Name", "Email"},
+		{"1", "Name", "a@b.c"},
+	}
+	var meta csvx.Metadata
+
+	// When
+	index, confidence := csvx.DetectHeader[StructRequired](rows, 10)
+	none, _ := csvx.DetectHeader[StructRequired](rows[:1], 10)
+	s, err := csvx.TryParser[StructRequired](rows, func(o *csvx.ParserOptions) {
+		o.DetectHeader = 10
+		o.Metadata = &meta
+	})
+
+	// Then
+	if index != 3 || confidence != 1 || none != -1 {
+		t.Error("Detect header error", index, confidence, none)
+	}
+	if err != nil || len(s) != 1 || s[0].Email != "a@b.c" || meta.HeaderRow != 3 || meta.HeaderConfidence != 1 {
+		t.Error("Parse detected header error", s, err, meta)
+	}
+}
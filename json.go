@@ -0,0 +1,258 @@
+package csvx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// JSONOptions configures CSVToJSON.
+type JSONOptions struct {
+	// NDJSON writes one object per line instead of a JSON array.
+	NDJSON bool
+
+	// InferTypes writes the numbers, true and false unquoted, and the empty cells as null.
+	// Numbers with leading zeros such as 00123, and numbers of more than 15 significant digits such as
+	// account numbers, stay strings so that JSON consumers do not alter them.
+	InferTypes bool
+
+	// Reader configures the csv.Reader like an option of Reader, such as Dialect.Reader.
+	Reader func(r *csv.Reader)
+}
+
+// CSVToJSON reads the csv r, whose first row is the header, and writes every other row to w as a JSON
+// object keyed by the header, in the order of the columns. The rows are read and written one at a time.
+//
+//	err := csvx.CSVToJSON(w, file, func(o *csvx.JSONOptions) {
+//		o.NDJSON = true
+//		o.InferTypes = true
+//	})
+func CSVToJSON(w io.Writer, r io.Reader, options ...func(o *JSONOptions)) error {
+	o := &JSONOptions{}
+	for _, option := range options {
+		option(o)
+	}
+
+	cr := csv.NewReader(TranscodeReader(r, UTF8))
+	cr.LazyQuotes = true
+	cr.Comma = ','
+	cr.Comment = '#'
+	if o.Reader != nil {
+		o.Reader(cr)
+	}
+	cr.FieldsPerRecord = -1
+
+	bw := bufio.NewWriter(w)
+	header, err := cr.Read()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	keys := make([][]byte, len(header))
+	for j, name := range header {
+		keys[j], _ = json.Marshal(name)
+	}
+
+	if !o.NDJSON {
+		_ = bw.WriteByte('[')
+	}
+	for i := 0; len(header) > 0; i++ {
+		record, rErr := cr.Read()
+		if errors.Is(rErr, io.EOF) {
+			if !o.NDJSON && i > 0 {
+				_ = bw.WriteByte('\n')
+			}
+			break
+		}
+		if rErr != nil {
+			return rErr
+		}
+		if !o.NDJSON {
+			if i > 0 {
+				_ = bw.WriteByte(',')
+			}
+			_ = bw.WriteByte('\n')
+		}
+		writeJSONObject(bw, keys, record, o.InferTypes)
+		if o.NDJSON {
+			_ = bw.WriteByte('\n')
+		}
+	}
+	if !o.NDJSON {
+		_, _ = bw.WriteString("]\n")
+	}
+	return bw.Flush()
+}
+
+func writeJSONObject(w *bufio.Writer, keys [][]byte, record []string, inferTypes bool) {
+	_ = w.WriteByte('{')
+	for j, key := range keys {
+		if j > 0 {
+			_ = w.WriteByte(',')
+		}
+		_, _ = w.Write(key)
+		_ = w.WriteByte(':')
+		value := ""
+		if j < len(record) {
+			value = record[j]
+		}
+		_, _ = w.Write(jsonValue(value, inferTypes))
+	}
+	_ = w.WriteByte('}')
+}
+
+// jsonValue returns the JSON of the cell, a string unless inferTypes finds a number, a boolean or null.
+func jsonValue(value string, inferTypes bool) []byte {
+	if inferTypes {
+		switch {
+		case value == "":
+			return []byte("null")
+		case value == "true" || value == "false":
+			return []byte(value)
+		case (value[0] == '-' || (value[0] >= '0' && value[0] <= '9')) && json.Valid([]byte(value)) && exactFloat(value):
+			return []byte(value)
+		}
+	}
+	text, _ := json.Marshal(value)
+	return text
+}
+
+// exactFloat reports whether the JSON number keeps its value as a float64, the number type of most JSON
+// consumers: at most 15 significant digits and an exponent within range.
+func exactFloat(number string) bool {
+	mantissa := number
+	if i := strings.IndexAny(number, "eE"); i >= 0 {
+		mantissa = number[:i]
+	}
+	if digits := strings.TrimLeft(strings.NewReplacer("-", "", ".", "").Replace(mantissa), "0"); len(digits) > 15 {
+		return false
+	}
+	_, err := strconv.ParseFloat(number, 64)
+	return err == nil
+}
+
+// JSONToCSV reads a JSON array of objects, or NDJSON, from r and writes it to w as csv with the quoting of
+// ManualConvertWithOptions, configured by options. The header is the union of the keys in the order they
+// first appear, nested objects being flattened to dotted paths such as "address.city". Arrays are written
+// as JSON and null as an empty cell. Numbers such as -5 are kept, the formulas being sanitized in the other
+// values. The objects are kept in memory to compute the header.
+//
+//	err := csvx.JSONToCSV(w, body, csvx.ExcelDialect().Convert)
+func JSONToCSV(w io.Writer, r io.Reader, options ...func(o *ConvertOptions)) error {
+	o := convertOptions(options)
+
+	br := bufio.NewReader(r)
+	dec := json.NewDecoder(br)
+	array := false
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			break
+		}
+		if b[0] == ' ' || b[0] == '\t' || b[0] == '\r' || b[0] == '\n' {
+			_, _ = br.ReadByte()
+			continue
+		}
+		array = b[0] == '['
+		break
+	}
+	if array {
+		if _, err := dec.Token(); err != nil {
+			return err
+		}
+	}
+
+	var keys []string
+	seen := map[string]bool{}
+	var objects []map[string]string
+	for !array || dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if !array && errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		object := map[string]string{}
+		if err := flattenJSON(raw, "", object, &keys, seen); err != nil {
+			return err
+		}
+		objects = append(objects, object)
+	}
+	if len(objects) == 0 {
+		return nil
+	}
+
+	rw := newRecordWriter(w, o)
+	if !o.Headerless {
+		if err := rw.Write(keys); err != nil {
+			return err
+		}
+	}
+	row := make([]string, len(keys))
+	for _, object := range objects {
+		for j, key := range keys {
+			row[j] = object[key]
+		}
+		if err := rw.Write(row); err != nil {
+			return err
+		}
+	}
+	return rw.Close()
+}
+
+// flattenJSON adds the values of the JSON object raw to object, keyed by their dotted path after prefix.
+func flattenJSON(raw json.RawMessage, prefix string, object map[string]string, keys *[]string, seen map[string]bool) error {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if token, err := dec.Token(); err != nil {
+		return err
+	} else if token != json.Delim('{') {
+		return errors.New("json value " + strconv.Quote(string(raw)) + " is not an object")
+	}
+
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := prefix + token.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		value = bytes.TrimSpace(value)
+
+		if len(value) > 0 && value[0] == '{' {
+			if err := flattenJSON(value, key+".", object, keys, seen); err != nil {
+				return err
+			}
+			continue
+		}
+		if !seen[key] {
+			seen[key] = true
+			*keys = append(*keys, key)
+		}
+		switch {
+		case string(value) == "null":
+			object[key] = ""
+		case len(value) > 0 && value[0] == '"':
+			var text string
+			if err := json.Unmarshal(value, &text); err != nil {
+				return err
+			}
+			object[key] = text
+		default:
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, value); err != nil {
+				return err
+			}
+			object[key] = compact.String()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package csvx_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+func TestCSVToJSON(t *testing.T) {
+	// Given
+	data := csvx.Utf8BOM + "ID,Code,Active,Name,Note\n1,00123,true,\"N,1\",\n2.5,-7,no,\"say \"\"hi\"\"\"\n"
+	expected := "[\n" +
+		`{"ID":1,"Code":"00123","Active":true,"Name":"N,1","Note":null},` + "\n" +
+		`{"ID":2.5,"Code":-7,"Active":"no","Name":"say \"hi\"","Note":null}` + "\n]\n"
+
+	// When
+	var buffer bytes.Buffer
+	err := csvx.CSVToJSON(&buffer, strings.NewReader(data), func(o *csvx.JSONOptions) {
+		o.InferTypes = true
+	})
+
+	// Then
+	if err != nil || buffer.String() != expected {
+		t.Errorf("CSVToJSON error: %s %v", buffer.String(), err)
+	}
+}
+
+func TestCSVToJSONLargeNumbers(t *testing.T) {
+	// Given
+	data := "Account,Amount,Rate,Huge\n12345678901234567890,123456789012345,0.000000000000001,1e400\n"
+	expected := `{"Account":"12345678901234567890","Amount":123456789012345,"Rate":0.000000000000001,"Huge":"1e400"}` + "\n"
+
+	// When
+	var buffer bytes.Buffer
+	err := csvx.CSVToJSON(&buffer, strings.NewReader(data), func(o *csvx.JSONOptions) {
+		o.NDJSON = true
+		o.InferTypes = true
+	})
+
+	// Then
+	if err != nil || buffer.String() != expected {
+		t.Errorf("CSVToJSON error: %s %v", buffer.String(), err)
+	}
+}
+
+func TestCSVToNDJSON(t *testing.T) {
+	// Given
+	data := "ID;Name\n1;N1\n2;N2\n"
+	expected := `{"ID":"1","Name":"N1"}` + "\n" + `{"ID":"2","Name":"N2"}` + "\n"
+
+	// When
+	var buffer bytes.Buffer
+	err := csvx.CSVToJSON(&buffer, strings.NewReader(data), func(o *csvx.JSONOptions) {
+		o.NDJSON = true
+		o.Reader = func(r *csv.Reader) {
+			r.Comma = ';'
+		}
+	})
+
+	// Then
+	if err != nil || buffer.String() != expected {
+		t.Errorf("CSVToJSON error: %s %v", buffer.String(), err)
+	}
+}
+
+func TestCSVToJSONEmpty(t *testing.T) {
+	// When
+	var buffer bytes.Buffer
+	err := csvx.CSVToJSON(&buffer, strings.NewReader(""))
+
+	// Then
+	if err != nil || buffer.String() != "[]\n" {
+		t.Errorf("CSVToJSON error: %q %v", buffer.String(), err)
+	}
+}
+
+func TestJSONToCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"array", ` [{"id": 1, "name": "N,1", "address": {"city": "BKK"}},
+			{"id": 2, "tags": ["a", "b"], "address": {"city": null, "zip": "10110"}, "note": "=1+1"}]`},
+		{"ndjson", `{"id": 1, "name": "N,1", "address": {"city": "BKK"}}
+			{"id": 2, "tags": ["a", "b"], "address": {"city": null, "zip": "10110"}, "note": "=1+1"}`},
+	}
+	expected := "id,name,address.city,tags,address.zip,note\n" +
+		"1,\"N,1\",BKK,,,\n" +
+		"2,,,\"[\"\"a\"\",\"\"b\"\"]\",10110,'=1+1\n"
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			var buffer bytes.Buffer
+			err := csvx.JSONToCSV(&buffer, strings.NewReader(tt.data), func(o *csvx.ConvertOptions) {
+				o.OmitBOM = true
+			})
+
+			// Then
+			if err != nil || buffer.String() != expected {
+				t.Errorf("JSONToCSV error: %q %v", buffer.String(), err)
+			}
+		})
+	}
+}
+
+func TestJSONToCSVNegativeNumbers(t *testing.T) {
+	// Given
+	data := `[{"a": -5, "b": -1.5e3, "c": "-x"}]`
+
+	// When
+	var buffer bytes.Buffer
+	err := csvx.JSONToCSV(&buffer, strings.NewReader(data), func(o *csvx.ConvertOptions) {
+		o.OmitBOM = true
+	})
+
+	// Then
+	if err != nil || buffer.String() != "a,b,c\n-5,-1.5e3,'-x\n" {
+		t.Errorf("JSONToCSV error: %q %v", buffer.String(), err)
+	}
+}
+
+func TestJSONToCSVNotObject(t *testing.T) {
+	// When
+	var buffer bytes.Buffer
+	err := csvx.JSONToCSV(&buffer, strings.NewReader(`[1]`))
+
+	// Then
+	if err == nil || err.Error() != `json value "1" is not an object` {
+		t.Error("JSONToCSV error", err)
+	}
+}
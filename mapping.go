@@ -0,0 +1,70 @@
+package csvx
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Mapping maps the headers of a csv file to the struct fields of T at runtime, overriding the `header` tags.
+// A field is given by its Go name, such as "Email" or "Address.Street" for a nested struct, or by one of its headers.
+// Mapping is a plain map, so it can be stored as JSON per customer.
+//
+//	m := csvx.Mapping{"E-mail address": "Email", "Customer": "Name"}
+//	s := csvx.Parser[Struct](rows, func(o *csvx.ParserOptions) {
+//		o.Mapping = m
+//	})
+type Mapping map[string]string
+
+// column returns the column of cols that the field of the mapping refers to.
+func (m Mapping) column(cols []*column, field string) *column {
+	for _, c := range cols {
+		if !c.rest && c.name == field {
+			return c
+		}
+	}
+	for _, c := range cols {
+		if c.rest {
+			continue
+		}
+		for _, name := range c.names {
+			if name == field {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// validate returns an error for the first header of the mapping whose field does not exist in cols.
+func (m Mapping) validate(cols []*column) error {
+	headers := make([]string, 0, len(m))
+	for header := range m {
+		headers = append(headers, header)
+	}
+	sort.Strings(headers)
+	for _, header := range headers {
+		if m.column(cols, m[header]) == nil {
+			return fmt.Errorf("mapping %q: field %q does not exist", header, m[header])
+		}
+	}
+	return nil
+}
+
+// headers returns the header of each mapped column, as written by Convert.
+func (m Mapping) headers(cols []*column) map[*column]string {
+	headers := map[*column]string{}
+	for header, field := range m {
+		if c := m.column(cols, field); c != nil {
+			if h, ok := headers[c]; !ok || header < h {
+				headers[c] = header
+			}
+		}
+	}
+	return headers
+}
+
+// ValidateMapping returns an error if a field of the mapping does not exist on the struct T.
+func ValidateMapping[T any](m Mapping) error {
+	return m.validate(columnsOf(reflect.TypeOf(model[T]{}.Data)))
+}
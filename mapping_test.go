@@ -0,0 +1,88 @@
+package csvx_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+func TestParserMapping(t *testing.T) {
+	// Given
+	var m csvx.Mapping
+	_ = json.Unmarshal([]byte(`{"Customer No":"ID","Full Name":"Name Space"}`), &m)
+	rows := [][]string{
+		{"Customer No", "Full Name", "ID"},
+		{"1", "Name1", "99"},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructType](rows, func(o *csvx.ParserOptions) {
+		o.Mapping = m
+	})
+
+	// Then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s[0].ID != 1 || s[0].Name != "Name1" {
+		t.Error("Parse mapping error", s)
+	}
+}
+
+func TestParserMappingNested(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"Road", "Town"},
+		{"S1", "C1"},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructNested](rows, func(o *csvx.ParserOptions) {
+		o.Mapping = csvx.Mapping{"Road": "Billing.Street", "Town": "Home City"}
+	})
+
+	// Then
+	if err != nil || s[0].Billing.Street != "S1" || s[0].Home.City != "C1" {
+		t.Error("Parse nested mapping error", err, s)
+	}
+}
+
+func TestValidateMapping(t *testing.T) {
+	// Given
+	m := csvx.Mapping{"Customer No": "ID", "Mail": "Email"}
+
+	// When
+	err := csvx.ValidateMapping[StructType](m)
+	_, pErr := csvx.TryParser[StructType]([][]string{{"ID"}}, func(o *csvx.ParserOptions) {
+		o.Mapping = m
+	})
+
+	// Then
+	if err == nil || err.Error() != `mapping "Mail": field "Email" does not exist` || pErr == nil {
+		t.Error("Validate mapping error:", err, pErr)
+	}
+}
+
+func TestConvertMapping(t *testing.T) {
+	// Given
+	m := []StructType{{ID: 1, Name: "N1", Age: 3}}
+	expected := csvx.Utf8BOM + `"Customer No","Name Space","Age"
+"1","N1","3"`
+
+	// When
+	result, err := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+		o.Mapping = csvx.Mapping{"Customer No": "ID"}
+	})
+	_, mErr := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+		o.Mapping = csvx.Mapping{"Mail": "Email"}
+	})
+
+	// Then
+	if err != nil || result != expected {
+		t.Error("Convert mapping error:\n", result, err)
+	}
+	if mErr == nil {
+		t.Error("Convert must validate the mapping")
+	}
+}
@@ -3,11 +3,10 @@ package csvx
 import (
 	"bufio"
 	"encoding/csv"
-	"fmt"
 	"io"
 	"mime/multipart"
 	"reflect"
-	"strconv"
+	"strings"
 )
 
 type model[T any] struct {
@@ -19,17 +18,31 @@ type model[T any] struct {
 // a single header field, where the first element is the header field name and the second element is the header field value.
 // If the header is empty or cannot be parsed, an empty slice will be returned. If an error occurs during the operation,
 // an error value will be returned.
+// UTF-8 and UTF-16 files with a BOM are detected, otherwise the file is transcoded from the given encoding.
+// Gzip files are decompressed and zip archives are read from their first csv file.
 // Ex:
 // file, _ := c.FormFile("file")
 // rows, err := csvx.FileHeaderReader(file)
-func FileHeaderReader(fileHeader *multipart.FileHeader) ([][]string, error) {
+// rows, err := csvx.FileHeaderReader(file, csvx.Windows874)
+func FileHeaderReader(fileHeader *multipart.FileHeader, encoding ...Encoding) ([][]string, error) {
 	file, err := fileHeader.Open()
 	if err != nil {
 		return [][]string{}, err
 	}
+	defer file.Close()
+
+	content, err := openCompressed(file, fileHeader.Size)
+	if err != nil {
+		return [][]string{}, err
+	}
+
+	enc := UTF8
+	if len(encoding) > 0 {
+		enc = encoding[0]
+	}
 
 	// Parse the file
-	r := csv.NewReader(bufio.NewReader(file))
+	r := csv.NewReader(bufio.NewReader(TranscodeReader(content, enc)))
 
 	// Read the records
 	_, err = r.Read()
@@ -69,32 +82,32 @@ func FileHeaderReader(fileHeader *multipart.FileHeader) ([][]string, error) {
 //	}
 //
 // s := csvx.ParserString[Struct](rows)
-func ParserString[T any](rows [][]string) []T {
+//
+// ParserString only sets string fields, use Parser for the other types.
+func ParserString[T any](rows [][]string, options ...func(o *ParserOptions)) []T {
 	var structs []T
 
 	if len(rows) == 0 {
 		return structs
 	}
 
-	header := rows[0]
-	for i, row := range rows {
-		if i == 0 {
-			continue
-		}
+	o := parserOptions(options)
+	f, err := newFrame(reflect.TypeOf(model[T]{}.Data), rows, o)
+	if err != nil {
+		return structs
+	}
 
+	targets := f.targets
+	for _, row := range rows[f.start:f.end] {
 		record := model[T]{}
 		structValue := reflect.ValueOf(&record.Data).Elem()
 
 		for j, field := range row {
-			structField := structValue.FieldByNameFunc(func(fieldName string) bool {
-				f, _ := reflect.TypeOf(record.Data).FieldByName(fieldName)
-				fieldTag := f.Tag.Get("header")
-				head := RemoveDoubleQuote(header[j])
-				return fieldTag == fmt.Sprintf("%v", head)
-			})
-
-			if structField.IsValid() {
-				structField.SetString(field)
+			if j >= len(targets) || targets[j] == nil {
+				continue
+			}
+			if targets[j].col.rest || targets[j].col.scalarType().Kind() == reflect.String {
+				_ = targets[j].parse(structValue, field, o)
 			}
 		}
 
@@ -104,112 +117,147 @@ func ParserString[T any](rows [][]string) []T {
 	return structs
 }
 
+// ParserOptions configures how Parser converts the cells of a row into struct fields.
+type ParserOptions struct {
+	// EnumCaseInsensitive matches enum labels regardless of letter case.
+	EnumCaseInsensitive bool
+
+	// ExtendedIntegers accepts base prefixes (0x, 0o, 0b), underscores and scientific notation
+	// such as 1e6 in integer columns.
+	ExtendedIntegers bool
+
+	// ValidateHeader checks the header row before parsing any data. TryParser returns a *HeaderError
+	// when a column with the `required` option is missing or when a column is duplicated.
+	// Only TryParser and TryParserByReader return the error, Parser and ParserString return no structs.
+	ValidateHeader bool
+
+	// RejectUnknownColumns also reports the columns that are not mapped to the struct as unexpected.
+	// It implies ValidateHeader.
+	RejectUnknownColumns bool
+
+	// Mapping maps the headers of the file to struct fields at runtime, overriding the `header` tags.
+	// TryParser returns an error if a field of the mapping does not exist.
+	Mapping Mapping
+
+	// Headerless parses every row as data, mapping the columns by the `no` tag in the order written by Convert:
+	// the field with `no:"3"` is read from the third column. A repeated column group without a `max` tag reads
+	// the trailing columns of the rows, TryParser returns an error when it is not the last column.
+	// The header options are ignored.
+	Headerless bool
+
+	// HeaderRow is the index of the header row, the rows before it are skipped as a preamble.
+	// With Headerless, it is the index of the first data row.
+	HeaderRow int
+
+	// FindHeader skips the rows from HeaderRow until a row containing every required column
+	// and at least one column of the struct is found, and uses it as the header row.
+	FindHeader bool
+
+	// DetectHeader scans that many rows from HeaderRow and uses the one that best matches the struct
+	// as the header row, see DetectHeader.
+	DetectHeader int
+
+	// TrailerRows is the number of rows dropped at the end, such as a record count.
+	TrailerRows int
+
+	// IsTrailer drops the last rows while it returns true, such as a "Total" row.
+	IsTrailer func(row []string) bool
+
+	// Metadata receives the skipped preamble and trailer rows, for reconciliation.
+	Metadata *Metadata
+
+	// HeaderNormalizer is applied to both the header row and the `header` tags before they are compared,
+	// for example NormalizeHeader. Headers are compared exactly when it is nil.
+	HeaderNormalizer func(header string) string
+}
+
+// Metadata is the part of the rows that Parser skipped.
+type Metadata struct {
+	// HeaderRow is the index of the header row, or -1 for ParserOptions.Headerless.
+	HeaderRow int
+	// HeaderConfidence is the score of the header row found by ParserOptions.DetectHeader.
+	HeaderConfidence float64
+	// Preamble is the rows before the header row.
+	Preamble [][]string
+	// Trailer is the rows dropped at the end.
+	Trailer [][]string
+}
+
+func parserOptions(options []func(o *ParserOptions)) *ParserOptions {
+	o := &ParserOptions{}
+	for _, option := range options {
+		option(o)
+	}
+	return o
+}
+
+func (o *ParserOptions) validatesHeader() bool {
+	return o.ValidateHeader || o.RejectUnknownColumns
+}
+
 // Parser parses the provided input data and returns the result.
 // It handles different formats based on the input type.
-func Parser[T any](rows [][]string) []T {
+// Cells that cannot be converted leave their field at its zero value, use TryParser to get the errors.
+//
+//	s := csvx.Parser[Struct](rows, func(o *csvx.ParserOptions) {
+//		o.EnumCaseInsensitive = true
+//	})
+func Parser[T any](rows [][]string, options ...func(o *ParserOptions)) []T {
+	structs, _ := TryParser[T](rows, options...)
+	return structs
+}
+
+// TryParser parses the provided input data like Parser, and also returns a ParseErrors
+// listing every cell that could not be converted into its struct field,
+// or a *HeaderError when the header row fails ParserOptions.ValidateHeader.
+func TryParser[T any](rows [][]string, options ...func(o *ParserOptions)) ([]T, error) {
 	var structs []T
 
 	if len(rows) == 0 {
-		return structs
+		return structs, nil
 	}
 
-	header := rows[0]
-	for i, row := range rows {
-		if i == 0 {
-			continue
-		}
+	o := parserOptions(options)
 
+	// Map each header to the column of the struct
+	f, err := newFrame(reflect.TypeOf(model[T]{}.Data), rows, o)
+	if err != nil {
+		return structs, err
+	}
+
+	var errs ParseErrors
+	targets := f.targets
+	for i := f.start; i < f.end; i++ {
+		row := rows[i]
 		record := model[T]{}
 		structValue := reflect.ValueOf(&record.Data).Elem()
 
 		for j, field := range row {
-			structField := structValue.FieldByNameFunc(func(fieldName string) bool {
-				f, _ := reflect.TypeOf(record.Data).FieldByName(fieldName)
-				fieldTag := f.Tag.Get("header")
-				head := RemoveDoubleQuote(header[j])
-				return fieldTag == fmt.Sprintf("%v", head)
-			})
-
-			if structField.IsValid() {
-				// Convert the value based on the field kind
-				switch structField.Kind() {
-				case reflect.Ptr:
-					// Handle pointer types
-					fieldType := structField.Type()
-					elemType := fieldType.Elem()
-					ptrValue := reflect.New(elemType)
-					switch elemType.Kind() {
-					case reflect.String:
-						ptrValue.Elem().SetString(field)
-					case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-						value, err := strconv.ParseInt(field, 10, 64)
-						if err == nil {
-							ptrValue.Elem().SetInt(value)
-						} else {
-							structField.Set(reflect.Zero(fieldType))
-							continue
-						}
-					case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-						value, err := strconv.ParseUint(field, 10, 64)
-						if err == nil {
-							ptrValue.Elem().SetUint(value)
-						} else {
-							structField.Set(reflect.Zero(fieldType))
-							continue
-						}
-					case reflect.Float32, reflect.Float64:
-						value, err := strconv.ParseFloat(field, 64)
-						if err == nil {
-							ptrValue.Elem().SetFloat(value)
-						} else {
-							structField.Set(reflect.Zero(fieldType))
-							continue
-						}
-					case reflect.Bool:
-						value, err := strconv.ParseBool(field)
-						if err == nil {
-							ptrValue.Elem().SetBool(value)
-						}
-					case reflect.Struct:
-						ptrValue.Elem().Set(reflect.ValueOf(field))
-					}
-					structField.Set(ptrValue)
-				default:
-					// Handle non-pointer types as before
-					switch structField.Kind() {
-					case reflect.String:
-						structField.SetString(field)
-					case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-						value, err := strconv.ParseInt(field, 10, 64)
-						if err == nil {
-							structField.SetInt(value)
-						}
-					case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-						value, err := strconv.ParseUint(field, 10, 64)
-						if err == nil {
-							structField.SetUint(value)
-						}
-					case reflect.Float32, reflect.Float64:
-						value, err := strconv.ParseFloat(field, 64)
-						if err == nil {
-							structField.SetFloat(value)
-						}
-					case reflect.Bool:
-						value, err := strconv.ParseBool(field)
-						if err == nil {
-							structField.SetBool(value)
-						}
-					case reflect.Struct:
-						structField.Set(reflect.ValueOf(field))
-					}
-				}
+			if j >= len(targets) || targets[j] == nil {
+				continue
+			}
+			if i == 0 && j == 0 {
+				field = strings.TrimPrefix(field, bom)
+			}
+			c := targets[j].col
+			if err := targets[j].parse(structValue, field, o); err != nil {
+				errs = append(errs, &ParseError{
+					Row:    i,
+					Column: f.columns[j],
+					Field:  c.name,
+					Value:  field,
+					Err:    err,
+				})
 			}
 		}
 
 		structs = append(structs, record.Data)
 	}
 
-	return structs
+	if len(errs) > 0 {
+		return structs, errs
+	}
+	return structs, nil
 }
 
 // ParserFunc processes the input data using a custom parsing function.
@@ -241,3 +289,16 @@ func ParserByReader[T any](ir *csv.Reader, delimiter ...rune) []T {
 		r.Comma = d
 	}))
 }
+
+// TryParserByReader parses data from a csv.Reader like TryParser, configured by options, such as
+// ParserOptions.ValidateHeader. The records are read with the Comma of ir.
+//
+//	s, err := csvx.TryParserByReader[Struct](csv.NewReader(file), func(o *csvx.ParserOptions) {
+//		o.ValidateHeader = true
+//	})
+func TryParserByReader[T any](ir *csv.Reader, options ...func(o *ParserOptions)) ([]T, error) {
+	comma := ir.Comma
+	return TryParser[T](Reader(ir, func(r *csv.Reader) {
+		r.Comma = comma
+	}), options...)
+}
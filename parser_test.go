@@ -2,7 +2,10 @@ package csvx_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/prongbang/csvx"
@@ -122,3 +125,166 @@ func TestParserFunc(t *testing.T) {
 		return nil
 	})
 }
+
+type StructBitSize struct {
+	Small int8    `header:"Small"`
+	Count uint16  `header:"Count"`
+	Ratio float32 `header:"Ratio"`
+}
+
+func TestParserOverflow(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"Small", "Count", "Ratio"},
+		{"300", "70000", "1e39"},
+		{"-128", "65535", "0.5"},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructBitSize](rows)
+
+	// Then
+	var parseErrors csvx.ParseErrors
+	if !errors.As(err, &parseErrors) || len(parseErrors) != 3 {
+		t.Fatal("Overflow must be reported:", err)
+	}
+	if !errors.Is(parseErrors[0], strconv.ErrRange) || parseErrors[0].Row != 1 || parseErrors[0].Column != "Small" {
+		t.Error("Overflow error detail:", parseErrors[0])
+	}
+	if s[0].Small != 0 || s[1].Small != -128 || s[1].Count != 65535 {
+		t.Error("Parse bit size error", s)
+	}
+}
+
+func TestParserExtendedIntegers(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"ID", "Name Space", "Age"},
+		{"0x1F", "Hex", "1"},
+		{"1_000", "Underscore", "1"},
+		{"1e6", "Scientific", "1"},
+		{"00123", "Zero padded", "1"},
+		{"1.5e0", "Fraction", "1"},
+	}
+
+	// When
+	_, strictErr := csvx.TryParser[StructType](rows[:2])
+	s, err := csvx.TryParser[StructType](rows, func(o *csvx.ParserOptions) {
+		o.ExtendedIntegers = true
+	})
+
+	// Then
+	if strictErr == nil {
+		t.Error("Extended integers must be opt-in")
+	}
+	var parseErrors csvx.ParseErrors
+	if !errors.As(err, &parseErrors) || len(parseErrors) != 1 || parseErrors[0].Row != 5 {
+		t.Error("Fraction must be reported:", err)
+	}
+	ids := []int64{31, 1000, 1000000, 123}
+	for i, id := range ids {
+		if s[i].ID != id {
+			t.Error("Parse extended integer error", s[i])
+		}
+	}
+}
+
+func TestParserHeaderless(t *testing.T) {
+	// Given
+	m := []StructType{{ID: 1, Name: "N1", Age: 3.5}, {ID: 2, Name: "N2"}}
+	expected := csvx.Utf8BOM + `"1","N1","3.5"
+"2","N2","0"`
+
+	// When
+	c, _ := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+		o.Headerless = true
+	})
+	s, err := csvx.TryParser[StructType](csvx.ByteReader([]byte(c)), func(o *csvx.ParserOptions) {
+		o.Headerless = true
+	})
+
+	// Then
+	if c != expected {
+		t.Error("Convert headerless error:\n", c)
+	}
+	if err != nil || !reflect.DeepEqual(s, m) {
+		t.Error("Parse headerless error", s, err)
+	}
+}
+
+type StructSparse struct {
+	A string `header:"A" no:"1"`
+	C string `header:"C" no:"3"`
+}
+
+func TestParserHeaderlessSparse(t *testing.T) {
+	// Given
+	rows := [][]string{{"a", "b", "c"}}
+	m := []StructSparse{{A: "a", C: "c"}}
+
+	// When
+	s, err := csvx.TryParser[StructSparse](rows, func(o *csvx.ParserOptions) {
+		o.Headerless = true
+	})
+	c, _ := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+		o.Headerless = true
+		o.IgnoreDoubleQuote = true
+	})
+	r, _ := csvx.TryParser[StructSparse](csvx.ByteReader([]byte(c)), func(o *csvx.ParserOptions) {
+		o.Headerless = true
+	})
+
+	// Then
+	if err != nil || !reflect.DeepEqual(s, m) {
+		t.Error("Parse headerless sparse error", s, err)
+	}
+	if c != csvx.Utf8BOM+"a,,c" || !reflect.DeepEqual(r, m) {
+		t.Errorf("Convert headerless sparse error: %q %v", c, r)
+	}
+}
+
+type StructHeaderlessGroup struct {
+	ID     int      `header:"ID" no:"1"`
+	Phones []string `header:"Phone *" no:"3"`
+}
+
+func TestParserHeaderlessGroup(t *testing.T) {
+	// Given
+	m := []StructHeaderlessGroup{{ID: 1, Phones: []string{"a", "b"}}, {ID: 2, Phones: []string{"c"}}}
+
+	// When
+	c, _ := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+		o.Headerless = true
+		o.IgnoreDoubleQuote = true
+	})
+	s, err := csvx.TryParser[StructHeaderlessGroup](csvx.ByteReader([]byte(c)), func(o *csvx.ParserOptions) {
+		o.Headerless = true
+	})
+	_, gErr := csvx.TryParser[StructGroup]([][]string{{"1", "01"}}, func(o *csvx.ParserOptions) {
+		o.Headerless = true
+	})
+
+	// Then
+	if c != csvx.Utf8BOM+"1,,a,b\n2,,c," || err != nil || !reflect.DeepEqual(s, m) {
+		t.Errorf("Headerless group error: %q %v %v", c, s, err)
+	}
+	if gErr == nil {
+		t.Error("A group without max before other columns must be an error")
+	}
+}
+
+func TestParserHeaderlessError(t *testing.T) {
+	// Given
+	rows := [][]string{{"x", "N1", "1"}}
+
+	// When
+	_, err := csvx.TryParser[StructType](rows, func(o *csvx.ParserOptions) {
+		o.Headerless = true
+	})
+
+	// Then
+	var parseErrors csvx.ParseErrors
+	if !errors.As(err, &parseErrors) || parseErrors[0].Row != 0 || parseErrors[0].Column != "ID" {
+		t.Error("Headerless error detail:", err)
+	}
+}
@@ -22,9 +22,25 @@ func ReadByte(filename string) []byte {
 
 // ByteReader creates an io.Reader from a byte slice.
 // It allows the byte data to be read sequentially as a stream.
+// UTF-8 and UTF-16 files with a BOM are detected, use ByteReaderWithEncoding for the other encodings.
+// Gzip files are decompressed and zip archives are read from their first csv file, use ZipReader
+// to read all of them. An empty slice is returned for a corrupt archive.
 func ByteReader(data []byte, options ...func(r *csv.Reader)) [][]string {
-	// Create a bytes.Reader from the byte slice
-	byteReader := bytes.NewReader(data)
+	return ByteReaderWithEncoding(data, UTF8, options...)
+}
+
+// ByteReaderWithEncoding reads data like ByteReader, transcoded from enc to UTF-8 after the decompression.
+// A UTF-8 or UTF-16 byte order mark takes precedence over enc.
+//
+//	rows := csvx.ByteReaderWithEncoding(data, csvx.Windows874)
+func ByteReaderWithEncoding(data []byte, enc Encoding, options ...func(r *csv.Reader)) [][]string {
+	content, err := openCompressed(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return [][]string{}
+	}
+
+	// Create a bytes.Reader from the byte slice, transcoded to UTF-8 without the BOM
+	byteReader := TranscodeReader(content, enc)
 
 	// Parse the file
 	r := csv.NewReader(byteReader)
@@ -34,6 +50,7 @@ func ByteReader(data []byte, options ...func(r *csv.Reader)) [][]string {
 
 // Reader wraps an existing io.Reader to provide additional functionality.
 // It may include features like buffering or line-by-line reading.
+// Wrap the io.Reader of r in TranscodeReader to read another encoding than UTF-8.
 func Reader(r *csv.Reader, options ...func(r *csv.Reader)) [][]string {
 	r.LazyQuotes = true
 	r.Comma = ','
@@ -0,0 +1,253 @@
+package csvx
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Table is a csv file with a header row, for files whose columns are not known at compile time.
+// The records are padded or truncated to the length of the header.
+type Table struct {
+	header  []string
+	records [][]string
+}
+
+// NewTable returns a Table of rows, such as the output of Reader, whose first row is the header.
+//
+//	t := csvx.NewTable(csvx.ByteReader(data))
+//	email := t.Get(0, "Email")
+func NewTable(rows [][]string) *Table {
+	t := &Table{}
+	if len(rows) == 0 {
+		return t
+	}
+	t.header = append([]string(nil), rows[0]...)
+	if len(t.header) > 0 {
+		t.header[0] = strings.TrimPrefix(t.header[0], bom)
+	}
+	t.records = make([][]string, len(rows)-1)
+	for i, row := range rows[1:] {
+		record := make([]string, len(t.header))
+		copy(record, row)
+		t.records[i] = record
+	}
+	return t
+}
+
+// Header returns the header row.
+func (t *Table) Header() []string {
+	return append([]string(nil), t.header...)
+}
+
+// Len returns the number of records, without the header row.
+func (t *Table) Len() int {
+	return len(t.records)
+}
+
+// Index returns the index of the column, or -1 if the table has no such column.
+func (t *Table) Index(column string) int {
+	for j, name := range t.header {
+		if name == column {
+			return j
+		}
+	}
+	return -1
+}
+
+// Get returns the cell of the record in the column, or an empty string if the record or the column does not exist.
+func (t *Table) Get(row int, column string) string {
+	j := t.Index(column)
+	if j < 0 || row < 0 || row >= len(t.records) {
+		return ""
+	}
+	return t.records[row][j]
+}
+
+// Set sets the cell of the record in the column. It returns an error if the record or the column does not exist.
+func (t *Table) Set(row int, column string, value string) error {
+	j, err := t.column(column)
+	if err != nil {
+		return err
+	}
+	if row < 0 || row >= len(t.records) {
+		return fmt.Errorf("row %d does not exist", row)
+	}
+	t.records[row][j] = value
+	return nil
+}
+
+// Column returns the cells of the column, or false if the table has no such column.
+func (t *Table) Column(column string) ([]string, bool) {
+	j := t.Index(column)
+	if j < 0 {
+		return nil, false
+	}
+	values := make([]string, len(t.records))
+	for i, record := range t.records {
+		values[i] = record[j]
+	}
+	return values, true
+}
+
+// Range calls fn for every record in order, with a function returning its cell in a column, until fn returns false.
+//
+//	t.Range(func(row int, get func(column string) string) bool {
+//		fmt.Println(get("Email"))
+//		return true
+//	})
+func (t *Table) Range(fn func(row int, get func(column string) string) bool) {
+	for i := range t.records {
+		i := i
+		if !fn(i, func(column string) string { return t.Get(i, column) }) {
+			return
+		}
+	}
+}
+
+// AddColumn appends a column with a cell for each record, the missing cells being empty.
+// It returns an error if the column already exists.
+func (t *Table) AddColumn(column string, values ...string) error {
+	if t.Index(column) >= 0 {
+		return fmt.Errorf("column %q already exists", column)
+	}
+	t.header = append(t.header, column)
+	for i := range t.records {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		t.records[i] = append(t.records[i], value)
+	}
+	return nil
+}
+
+// RemoveColumn removes the column. It returns an error if the column does not exist.
+func (t *Table) RemoveColumn(column string) error {
+	j, err := t.column(column)
+	if err != nil {
+		return err
+	}
+	t.header = append(t.header[:j:j], t.header[j+1:]...)
+	for i, record := range t.records {
+		t.records[i] = append(record[:j:j], record[j+1:]...)
+	}
+	return nil
+}
+
+// RenameColumn renames the column. It returns an error if the column does not exist or if the new name is taken.
+func (t *Table) RenameColumn(column string, name string) error {
+	j, err := t.column(column)
+	if err != nil {
+		return err
+	}
+	if k := t.Index(name); k >= 0 && k != j {
+		return fmt.Errorf("column %q already exists", name)
+	}
+	t.header[j] = name
+	return nil
+}
+
+// ReorderColumns moves the columns to the front in the given order, the other columns keeping their order after them.
+// It returns an error if a column does not exist.
+func (t *Table) ReorderColumns(columns ...string) error {
+	order := make([]int, 0, len(t.header))
+	moved := map[int]bool{}
+	for _, column := range columns {
+		j, err := t.column(column)
+		if err != nil {
+			return err
+		}
+		if !moved[j] {
+			moved[j] = true
+			order = append(order, j)
+		}
+	}
+	for j := range t.header {
+		if !moved[j] {
+			order = append(order, j)
+		}
+	}
+
+	t.header = reorder(t.header, order)
+	for i, record := range t.records {
+		t.records[i] = reorder(record, order)
+	}
+	return nil
+}
+
+func reorder(values []string, order []int) []string {
+	reordered := make([]string, len(order))
+	for k, j := range order {
+		reordered[k] = values[j]
+	}
+	return reordered
+}
+
+// column returns the index of the column, or an error if the table has no such column.
+func (t *Table) column(column string) (int, error) {
+	j := t.Index(column)
+	if j < 0 {
+		return -1, fmt.Errorf("column %q does not exist", column)
+	}
+	return j, nil
+}
+
+// Rows returns the header row followed by the records, the input of Parser.
+func (t *Table) Rows() [][]string {
+	rows := make([][]string, 0, len(t.records)+1)
+	rows = append(rows, t.Header())
+	for _, record := range t.records {
+		rows = append(rows, append([]string(nil), record...))
+	}
+	return rows
+}
+
+// Maps returns the records as maps from the header to the cell. The first of duplicated columns is kept.
+func (t *Table) Maps() []map[string]string {
+	maps := make([]map[string]string, len(t.records))
+	for i, record := range t.records {
+		m := make(map[string]string, len(t.header))
+		for j := len(t.header) - 1; j >= 0; j-- {
+			m[t.header[j]] = record[j]
+		}
+		maps[i] = m
+	}
+	return maps
+}
+
+// ParseTable binds the records of the table to structs with TryParser.
+//
+//	s, err := csvx.ParseTable[Struct](t)
+func ParseTable[T any](t *Table, options ...func(o *ParserOptions)) ([]T, error) {
+	return TryParser[T](t.Rows(), options...)
+}
+
+// Write writes the table to w with the quoting of ManualConvertWithOptions, configured by options.
+//
+//	err := t.Write(w, csvx.ExcelDialect().Convert)
+func (t *Table) Write(w io.Writer, options ...func(o *ConvertOptions)) error {
+	o := convertOptions(options)
+	rw := newRecordWriter(w, o)
+	if !o.Headerless {
+		if err := rw.Write(t.header); err != nil {
+			return err
+		}
+	}
+	for _, record := range t.records {
+		if err := rw.Write(record); err != nil {
+			return err
+		}
+	}
+	return rw.Close()
+}
+
+// Convert returns the table as csv, see Write.
+func (t *Table) Convert(options ...func(o *ConvertOptions)) (string, error) {
+	var buffer bytes.Buffer
+	if err := t.Write(&buffer, options...); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
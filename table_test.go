@@ -0,0 +1,161 @@
+package csvx_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+func newTable() *csvx.Table {
+	return csvx.NewTable([][]string{
+		{csvx.Utf8BOM + "ID", "Name Space", "Email"},
+		{"1", "N1", "n1@mail.com"},
+		{"2", "N2"},
+	})
+}
+
+func TestTableGet(t *testing.T) {
+	// Given
+	table := newTable()
+
+	// When
+	email := table.Get(0, "Email")
+	missing := table.Get(1, "Email")
+	unknown := table.Get(0, "Phone")
+	ids, ok := table.Column("ID")
+
+	// Then
+	if email != "n1@mail.com" || missing != "" || unknown != "" || table.Len() != 2 {
+		t.Error("Get error", email, missing, unknown)
+	}
+	if !ok || !reflect.DeepEqual(ids, []string{"1", "2"}) {
+		t.Error("Column error", ids)
+	}
+}
+
+func TestTableRange(t *testing.T) {
+	// Given
+	table := newTable()
+
+	// When
+	var names []string
+	table.Range(func(row int, get func(column string) string) bool {
+		names = append(names, get("Name Space"))
+		return row < 0
+	})
+
+	// Then
+	if !reflect.DeepEqual(names, []string{"N1"}) {
+		t.Error("Range error", names)
+	}
+}
+
+func TestTableColumns(t *testing.T) {
+	// Given
+	table := newTable()
+
+	// When
+	errs := []error{
+		table.AddColumn("Phone", "0876"),
+		table.RemoveColumn("Email"),
+		table.RenameColumn("Name Space", "Name"),
+		table.ReorderColumns("Phone", "Name"),
+		table.Set(1, "Phone", "0999"),
+	}
+
+	// Then
+	for _, err := range errs {
+		if err != nil {
+			t.Fatal("Table error", err)
+		}
+	}
+	expected := [][]string{{"Phone", "Name", "ID"}, {"0876", "N1", "1"}, {"0999", "N2", "2"}}
+	if !reflect.DeepEqual(table.Rows(), expected) {
+		t.Error("Table error", table.Rows())
+	}
+}
+
+func TestTableColumnErrors(t *testing.T) {
+	// Given
+	table := newTable()
+
+	// When
+	errs := []error{
+		table.AddColumn("ID"),
+		table.RemoveColumn("Phone"),
+		table.RenameColumn("ID", "Email"),
+		table.ReorderColumns("Phone"),
+		table.Set(5, "ID", "1"),
+	}
+
+	// Then
+	expected := []string{
+		`column "ID" already exists`,
+		`column "Phone" does not exist`,
+		`column "Email" already exists`,
+		`column "Phone" does not exist`,
+		`row 5 does not exist`,
+	}
+	for i, err := range errs {
+		if err == nil || err.Error() != expected[i] {
+			t.Error("Table error", i, err)
+		}
+	}
+}
+
+func TestTableMaps(t *testing.T) {
+	// Given
+	table := newTable()
+
+	// When
+	maps := table.Maps()
+
+	// Then
+	expected := []map[string]string{
+		{"ID": "1", "Name Space": "N1", "Email": "n1@mail.com"},
+		{"ID": "2", "Name Space": "N2", "Email": ""},
+	}
+	if !reflect.DeepEqual(maps, expected) {
+		t.Error("Maps error", maps)
+	}
+}
+
+func TestParseTable(t *testing.T) {
+	// Given
+	table := newTable()
+
+	// When
+	s, err := csvx.ParseTable[MyStruct](table)
+
+	// Then
+	if err != nil || len(s) != 2 || s[1].ID != 2 || s[1].Name != "N2" {
+		t.Error("ParseTable error", s, err)
+	}
+}
+
+func TestTableConvert(t *testing.T) {
+	// Given
+	table := newTable()
+
+	// When
+	result, err := table.Convert(csvx.ExcelDialect().Convert)
+
+	// Then
+	if err != nil || result != csvx.Utf8BOM+"ID,Name Space,Email\r\n1,N1,n1@mail.com\r\n2,N2,\r\n" {
+		t.Errorf("Convert error: %q %v", result, err)
+	}
+}
+
+func TestTableConvertSanitizesFormulas(t *testing.T) {
+	// Given
+	table := csvx.NewTable([][]string{{"A", "B"}, {"-5", "=cmd"}})
+
+	// When
+	result, err := table.Convert()
+
+	// Then
+	if err != nil || result != csvx.Utf8BOM+"A,B\n-5,'=cmd\n" {
+		t.Errorf("Convert error: %q %v", result, err)
+	}
+}
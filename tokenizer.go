@@ -0,0 +1,214 @@
+package csvx
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Tokenizer reads csv records whose fields are separated by a Delimiter of any length, such as "||", "~|~"
+// or the ASCII unit separator "\x1f", and whose records end with a custom Terminator, such as the ASCII
+// record separator "\x1e". Fields are quoted like encoding/csv with LazyQuotes: a field starting with
+// a double quote ends at the next quote followed by the delimiter, the terminator or the end of the input,
+// and "" is an escaped quote. Empty records are skipped.
+type Tokenizer struct {
+	// Delimiter separates the fields, "," when empty.
+	Delimiter string
+
+	// Terminator ends the records, "\n" or "\r\n" when empty.
+	Terminator string
+
+	r *bufio.Reader
+}
+
+// NewTokenizer returns a Tokenizer reading from r, configured by options.
+//
+//	t := csvx.NewTokenizer(file, func(t *csvx.Tokenizer) {
+//		t.Delimiter = "~|~"
+//	})
+func NewTokenizer(r io.Reader, options ...func(t *Tokenizer)) *Tokenizer {
+	t := &Tokenizer{r: bufio.NewReader(r)}
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// Read reads the next record. It returns io.EOF at the end of the input.
+func (t *Tokenizer) Read() ([]string, error) {
+	if t.Delimiter == "" {
+		t.Delimiter = ","
+	}
+	if strings.Contains(t.Delimiter, `"`) || t.Delimiter == t.Terminator {
+		return nil, errors.New("invalid delimiter " + strconv.Quote(t.Delimiter))
+	}
+
+	for {
+		record, err := t.readRecord()
+		if err != nil {
+			return nil, err
+		}
+		if len(record) > 1 || record[0] != "" {
+			return record, nil
+		}
+	}
+}
+
+// ReadAll reads the remaining records.
+func (t *Tokenizer) ReadAll() ([][]string, error) {
+	rows := [][]string{}
+	for {
+		record, err := t.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, record)
+	}
+}
+
+// readRecord reads the fields until the terminator or the end of the input.
+// It returns io.EOF when no character is left.
+func (t *Tokenizer) readRecord() ([]string, error) {
+	if _, err := t.r.Peek(1); err != nil {
+		return nil, err
+	}
+
+	var record []string
+	var field bytes.Buffer
+	for {
+		if next, _ := t.r.Peek(1); len(next) == 1 && next[0] == '"' {
+			_, _ = t.r.ReadByte()
+			if err := t.readQuoted(&field); err != nil {
+				return nil, err
+			}
+		}
+
+		// Unquoted part of the field
+		for {
+			if t.skip(t.Delimiter) {
+				record = append(record, field.String())
+				field.Reset()
+				break
+			}
+			if t.skipTerminator() {
+				return append(record, field.String()), nil
+			}
+			b, err := t.r.ReadByte()
+			if errors.Is(err, io.EOF) {
+				return append(record, field.String()), nil
+			}
+			if err != nil {
+				return nil, err
+			}
+			field.WriteByte(b)
+		}
+	}
+}
+
+// readQuoted reads a quoted field after its opening quote.
+func (t *Tokenizer) readQuoted(field *bytes.Buffer) error {
+	for {
+		b, err := t.r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if b != '"' {
+			field.WriteByte(b)
+			continue
+		}
+		next, _ := t.r.Peek(1)
+		switch {
+		case len(next) == 1 && next[0] == '"':
+			_, _ = t.r.ReadByte()
+			field.WriteByte('"')
+		case len(next) == 0 || t.at(t.Delimiter) || t.atTerminator():
+			return nil
+		default:
+			// Lazy quote
+			field.WriteByte('"')
+		}
+	}
+}
+
+// at reports whether the input continues with s.
+func (t *Tokenizer) at(s string) bool {
+	next, _ := t.r.Peek(len(s))
+	return string(next) == s
+}
+
+// skip consumes s if the input continues with it.
+func (t *Tokenizer) skip(s string) bool {
+	if !t.at(s) {
+		return false
+	}
+	_, _ = t.r.Discard(len(s))
+	return true
+}
+
+func (t *Tokenizer) atTerminator() bool {
+	if t.Terminator != "" {
+		return t.at(t.Terminator)
+	}
+	return t.at("\n") || t.at("\r\n")
+}
+
+func (t *Tokenizer) skipTerminator() bool {
+	if t.Terminator != "" {
+		return t.skip(t.Terminator)
+	}
+	return t.skip("\n") || t.skip("\r\n")
+}
+
+// TokenReader reads all the records of r with a Tokenizer, like Reader for multi-character delimiters.
+// The records read before an error are returned.
+//
+//	rows := csvx.TokenReader(file, func(t *csvx.Tokenizer) {
+//		t.Delimiter = "||"
+//	})
+//	s := csvx.Parser[Struct](rows)
+func TokenReader(r io.Reader, options ...func(t *Tokenizer)) [][]string {
+	rows, _ := NewTokenizer(TranscodeReader(r, UTF8), options...).ReadAll()
+	return rows
+}
+
+// writeRecord writes the record with a delimiter and a terminator of any length, quoting the fields
+// like csv.Writer.
+func writeRecord(w *bytes.Buffer, record []string, delimiter, terminator string) {
+	for i, field := range record {
+		if i > 0 {
+			w.WriteString(delimiter)
+		}
+		if !fieldNeedsQuotes(field, delimiter, terminator) {
+			w.WriteString(field)
+			continue
+		}
+		w.WriteByte('"')
+		w.WriteString(strings.ReplaceAll(field, `"`, `""`))
+		w.WriteByte('"')
+	}
+	w.WriteString(terminator)
+}
+
+// fieldNeedsQuotes reports whether the field must be quoted, following csv.Writer.
+func fieldNeedsQuotes(field, delimiter, terminator string) bool {
+	if field == "" {
+		return false
+	}
+	if field == `\.` || strings.Contains(field, delimiter) || strings.ContainsAny(field, "\"\r\n") ||
+		(terminator != "" && strings.Contains(field, terminator)) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(field)
+	return unicode.IsSpace(r)
+}
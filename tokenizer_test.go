@@ -0,0 +1,112 @@
+package csvx_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+func TestTokenizer(t *testing.T) {
+	tests := []struct {
+		name       string
+		text       string
+		delimiter  string
+		terminator string
+		expected   [][]string
+	}{
+		{"double pipe", "ID||Name\n1||\"N||1\"\r\n\n2||N2", "||", "", [][]string{{"ID", "Name"}, {"1", "N||1"}, {"2", "N2"}}},
+		{"tilde pipe", "ID~|~Name\n1~|~\"say \"\"hi\"\"\"\n", "~|~", "", [][]string{{"ID", "Name"}, {"1", `say "hi"`}}},
+		{"ascii separators", "ID\x1fName\x1e1\x1fN\n1\x1e", "\x1f", "\x1e", [][]string{{"ID", "Name"}, {"1", "N\n1"}}},
+		{"lazy quotes", "a,b\"c,\"d\"e\"\n", "", "", [][]string{{"a", "b\"c", "d\"e"}}},
+		{"empty fields", "a,,\n", "", "", [][]string{{"a", "", ""}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// When
+			rows, err := csvx.NewTokenizer(strings.NewReader(tt.text), func(t *csvx.Tokenizer) {
+				t.Delimiter = tt.delimiter
+				t.Terminator = tt.terminator
+			}).ReadAll()
+
+			// Then
+			if err != nil || !reflect.DeepEqual(rows, tt.expected) {
+				t.Errorf("Tokenizer error: %q %v", rows, err)
+			}
+		})
+	}
+}
+
+func TestTokenizerRead(t *testing.T) {
+	// Given
+	tokenizer := csvx.NewTokenizer(strings.NewReader("a||b"), func(t *csvx.Tokenizer) {
+		t.Delimiter = "||"
+	})
+
+	// When
+	record, err := tokenizer.Read()
+	_, eof := tokenizer.Read()
+
+	// Then
+	if err != nil || !reflect.DeepEqual(record, []string{"a", "b"}) || !errors.Is(eof, io.EOF) {
+		t.Error("Read error", record, err, eof)
+	}
+}
+
+func TestTokenReaderParser(t *testing.T) {
+	// Given
+	rows := csvx.TokenReader(strings.NewReader(csvx.Utf8BOM+"ID~|~Name Space\n1~|~N1\n"), func(t *csvx.Tokenizer) {
+		t.Delimiter = "~|~"
+	})
+
+	// When
+	s := csvx.Parser[MyStruct](rows)
+
+	// Then
+	if len(s) != 1 || s[0].ID != 1 || s[0].Name != "N1" {
+		t.Error("Parser error", s)
+	}
+}
+
+func TestConvertWithOptionsDelimiter(t *testing.T) {
+	// Given
+	m := []MyStruct{{ID: 1, Name: "N1"}, {ID: 2, Name: "N2"}}
+
+	// When
+	result, err := csvx.ConvertWithOptions(m, func(o *csvx.ConvertOptions) {
+		o.Delimiter = "||"
+		o.LineTerminator = "\x1e"
+		o.OmitBOM = true
+	})
+
+	// Then
+	if err != nil || result != "\"ID\"||\"Name Space\"\x1e\"1\"||\"N1\"\x1e\"2\"||\"N2\"" {
+		t.Errorf("Convert error: %q %v", result, err)
+	}
+}
+
+func TestManualConvertWithOptionsDelimiter(t *testing.T) {
+	// Given
+	m := []MyStruct{{ID: 1, Name: "a||b"}}
+
+	// When
+	result, _ := csvx.ManualConvertWithOptions(m, []string{"ID", "Name"}, func(data MyStruct) []string {
+		return []string{"1", data.Name}
+	}, func(o *csvx.ConvertOptions) {
+		o.Delimiter = "||"
+		o.LineTerminator = "\x1e"
+		o.OmitBOM = true
+	})
+	rows, _ := csvx.NewTokenizer(strings.NewReader(result), func(t *csvx.Tokenizer) {
+		t.Delimiter = "||"
+		t.Terminator = "\x1e"
+	}).ReadAll()
+
+	// Then
+	if result != "ID||Name\x1e1||\"a||b\"\x1e" || !reflect.DeepEqual(rows, [][]string{{"ID", "Name"}, {"1", "a||b"}}) {
+		t.Errorf("ManualConvert error: %q %q", result, rows)
+	}
+}
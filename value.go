@@ -0,0 +1,228 @@
+package csvx
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// parseValue converts the text of a cell and sets it to the field v of the column.
+// An empty cell leaves a non-string field at its zero value.
+func (c *column) parseValue(v reflect.Value, text string, o *ParserOptions) error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.isList(v.Type()) {
+		return c.parseList(v, text, o)
+	}
+	return c.parseItem(v, text, o)
+}
+
+// parseList splits the text of a cell on the `split` tag and converts each element.
+// An empty cell gives a nil slice.
+func (c *column) parseList(v reflect.Value, text string, o *ParserOptions) error {
+	if text == "" {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+
+	parts := strings.Split(text, c.split)
+	list := v
+	if v.Kind() == reflect.Slice {
+		list = reflect.MakeSlice(v.Type(), len(parts), len(parts))
+	} else if len(parts) > v.Len() {
+		return fmt.Errorf("%d elements do not fit in %s", len(parts), v.Type())
+	}
+	for i, part := range parts {
+		if err := c.parseItem(list.Index(i), strings.TrimSpace(part), o); err != nil {
+			return fmt.Errorf("element %d: %w", i+1, err)
+		}
+	}
+	v.Set(list)
+	return nil
+}
+
+func (c *column) parseItem(v reflect.Value, text string, o *ParserOptions) error {
+	if IsPointer(v.Type()) {
+		elemType := v.Type().Elem()
+		if text == "" && !c.keepsEmpty(elemType) {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		ptrValue := reflect.New(elemType)
+		if err := c.parseScalar(ptrValue.Elem(), text, o); err != nil {
+			v.Set(reflect.Zero(v.Type()))
+			return err
+		}
+		v.Set(ptrValue)
+		return nil
+	}
+
+	return c.parseScalar(v, text, o)
+}
+
+func (c *column) parseScalar(v reflect.Value, text string, o *ParserOptions) error {
+	if text == "" && !c.keepsEmpty(v.Type()) {
+		return nil
+	}
+	if e := c.enumOf(); e != nil {
+		return e.parse(v, text, o.EnumCaseInsensitive)
+	}
+	if ok, err := parseDecimal(v, text); ok {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := parseInt(text, v.Type().Bits(), o.ExtendedIntegers)
+		if err != nil {
+			return err
+		}
+		v.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		value, err := parseUint(text, v.Type().Bits(), o.ExtendedIntegers)
+		if err != nil {
+			return err
+		}
+		v.SetUint(value)
+	case reflect.Float32, reflect.Float64:
+		value, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(value)
+	case reflect.Bool:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(value)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// parseInt parses a signed integer that fits in bitSize bits.
+// With extended, it also accepts base prefixes, underscores and scientific notation such as 1e6.
+func parseInt(text string, bitSize int, extended bool) (int64, error) {
+	if !extended {
+		return strconv.ParseInt(text, 10, bitSize)
+	}
+	digits, base := integerText(text)
+	value, err := strconv.ParseInt(digits, base, bitSize)
+	if errors.Is(err, strconv.ErrSyntax) {
+		n, nErr := parseScientific(digits)
+		if nErr != nil {
+			return 0, err
+		}
+		return strconv.ParseInt(n, 10, bitSize)
+	}
+	return value, err
+}
+
+// parseUint parses an unsigned integer that fits in bitSize bits, see parseInt.
+func parseUint(text string, bitSize int, extended bool) (uint64, error) {
+	if !extended {
+		return strconv.ParseUint(text, 10, bitSize)
+	}
+	digits, base := integerText(text)
+	value, err := strconv.ParseUint(digits, base, bitSize)
+	if errors.Is(err, strconv.ErrSyntax) {
+		n, nErr := parseScientific(digits)
+		if nErr != nil {
+			return 0, err
+		}
+		return strconv.ParseUint(n, 10, bitSize)
+	}
+	return value, err
+}
+
+// integerText returns the text and base to parse an extended integer with.
+// Only the 0x, 0o and 0b prefixes select another base, so zero padded numbers such as 00123 stay decimal.
+func integerText(text string) (string, int) {
+	digits := strings.TrimLeft(text, "+-")
+	if len(digits) > 1 && digits[0] == '0' && strings.ContainsRune("xXoObB", rune(digits[1])) {
+		return text, 0
+	}
+	return strings.ReplaceAll(text, "_", ""), 10
+}
+
+// parseScientific returns the integer written in scientific notation, such as 1e6 or 1.5E3, in base 10.
+func parseScientific(text string) (string, error) {
+	if !strings.ContainsAny(text, "eE") {
+		return "", strconv.ErrSyntax
+	}
+	r, ok := new(big.Rat).SetString(text)
+	if !ok || !r.IsInt() {
+		return "", strconv.ErrSyntax
+	}
+	return r.Num().String(), nil
+}
+
+// keepsEmpty reports whether an empty cell is a value of type t rather than a missing value.
+func (c *column) keepsEmpty(t reflect.Type) bool {
+	return t.Kind() == reflect.String && t != decimalType && c.enumOf() == nil
+}
+
+// formatValue returns the text of the field v of the column.
+// It returns false when v is a nil pointer or a nil slice, so the caller can use the `default` tag.
+func (c *column) formatValue(v reflect.Value) (string, bool) {
+	if c.isList(v.Type()) {
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return "", false
+		}
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i], _ = c.formatItem(v.Index(i))
+		}
+		return strings.Join(items, c.split), true
+	}
+	return c.formatItem(v)
+}
+
+func (c *column) formatItem(v reflect.Value) (string, bool) {
+	pointer := IsPointer(v.Type())
+	if pointer {
+		if !v.Elem().IsValid() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+
+	if e := c.enumOf(); e != nil {
+		if label, ok := e.label(v); ok {
+			return label, true
+		}
+	}
+
+	text, exact := formatDecimal(v)
+	if !exact {
+		if !pointer && IsFloat(v.Type()) {
+			text = F64ToString(v.Float())
+		} else {
+			text = fmt.Sprintf("%v", v)
+		}
+	}
+
+	if c.scaled {
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			text = roundDecimal(text, c.scale)
+		case reflect.Float32, reflect.Float64:
+			// Round the shortest decimal of the float, not its binary value
+			text = roundDecimal(strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), c.scale)
+		default:
+			if exact {
+				text = roundDecimal(text, c.scale)
+			}
+		}
+	}
+	return text, true
+}
@@ -0,0 +1,70 @@
+package csvx_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+type StructList struct {
+	ID     int      `header:"ID" no:"1"`
+	Tags   []string `header:"Tags" no:"2" split:";"`
+	Scores []*int   `header:"Scores" no:"3" split:"|"`
+	Levels [2]Level `header:"Levels" no:"4" split:";"`
+}
+
+func TestParserList(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"ID", "Tags", "Scores", "Levels"},
+		{"1", "a; b;c", "1|2", "Low;High"},
+		{"2", "", "", ""},
+	}
+
+	// When
+	s, err := csvx.TryParser[StructList](rows)
+
+	// Then
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(s[0].Tags, []string{"a", "b", "c"}) || *s[0].Scores[1] != 2 || s[0].Levels[1] != 2 {
+		t.Error("Parse list error", s[0])
+	}
+	if s[1].Tags != nil || s[1].Scores != nil {
+		t.Error("Parse empty list error", s[1])
+	}
+}
+
+func TestParserListError(t *testing.T) {
+	// Given
+	rows := [][]string{
+		{"Scores", "Levels"},
+		{"1|x", "Low;High;Low"},
+	}
+
+	// When
+	_, err := csvx.TryParser[StructList](rows)
+
+	// Then
+	if errs, ok := err.(csvx.ParseErrors); !ok || len(errs) != 2 {
+		t.Error("List errors must be reported:", err)
+	}
+}
+
+func TestConvertList(t *testing.T) {
+	// Given
+	one := 1
+	m := []StructList{{ID: 1, Tags: []string{"a", "b"}, Scores: []*int{&one}, Levels: [2]Level{1, 2}}}
+	expected := csvx.Utf8BOM + `"ID","Tags","Scores","Levels"
+"1","a;b","1","Low;High"`
+
+	// When
+	result := csvx.Convert(m)
+
+	// Then
+	if result != expected {
+		t.Error("Convert list error:\n", result)
+	}
+}
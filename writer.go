@@ -0,0 +1,87 @@
+package csvx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"io"
+)
+
+// recordWriter writes records to w one at a time with the quoting of csv.Writer, configured by ConvertOptions.
+type recordWriter struct {
+	o       *ConvertOptions
+	w       io.Writer
+	gzip    *gzip.Writer
+	csv     *csv.Writer
+	buffer  bytes.Buffer
+	offset  int
+	started bool
+}
+
+func newRecordWriter(w io.Writer, o *ConvertOptions) *recordWriter {
+	rw := &recordWriter{o: o, w: w}
+	if o.Gzip {
+		rw.gzip = gzip.NewWriter(w)
+		rw.w = rw.gzip
+	}
+
+	// encoding/csv only supports single rune delimiters and "\n" or "\r\n"
+	if o.Delimiter == "" && (o.LineTerminator == "" || o.LineTerminator == "\n" || o.LineTerminator == "\r\n") {
+		rw.csv = csv.NewWriter(&rw.buffer)
+		if o.Comma != 0 {
+			rw.csv.Comma = o.Comma
+		}
+		rw.csv.UseCRLF = o.LineTerminator == "\r\n"
+	}
+	return rw
+}
+
+// Write writes the record, with the formulas sanitized and the text protected. The byte order mark
+// and the "sep=" line are written before the first record.
+func (rw *recordWriter) Write(record []string) error {
+	if !rw.started {
+		rw.started = true
+		if !rw.o.OmitBOM {
+			if _, err := rw.w.Write(rw.o.Encoding.BOM()); err != nil {
+				return err
+			}
+		}
+		rw.buffer.WriteString(rw.o.sepLine())
+	}
+
+	row := rw.o.sanitizeRow(record)
+	if rw.csv != nil {
+		if err := rw.csv.Write(row); err != nil {
+			return err
+		}
+		rw.csv.Flush()
+		if err := rw.csv.Error(); err != nil {
+			return err
+		}
+	} else {
+		writeRecord(&rw.buffer, row, rw.o.comma(), rw.o.lineTerminator())
+	}
+
+	text := rw.buffer.String()
+	rw.buffer.Reset()
+	data, err := Encode(text, rw.o.Encoding)
+	var encodeErr *EncodeError
+	if errors.As(err, &encodeErr) {
+		encodeErr.Offset += rw.offset
+	}
+	if err != nil {
+		return err
+	}
+	rw.offset += len(text)
+	_, err = rw.w.Write(data)
+	return err
+}
+
+// Close flushes the gzip stream. It does not close the underlying io.Writer.
+func (rw *recordWriter) Close() error {
+	if rw.gzip != nil {
+		return rw.gzip.Close()
+	}
+	return nil
+}
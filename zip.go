@@ -0,0 +1,100 @@
+package csvx
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"reflect"
+)
+
+// ZipWriter writes a zip archive of csv files to an io.Writer, such as an http.ResponseWriter,
+// every file being converted with the same ConvertOptions.
+type ZipWriter struct {
+	// Manifest is the name of a csv file listing the row count and the SHA-256 checksum of every file,
+	// written by Close. No manifest is written when it is empty.
+	Manifest string
+
+	zw      *zip.Writer
+	options []func(o *ConvertOptions)
+	entries []ManifestEntry
+}
+
+// ManifestEntry is a row of the ZipWriter manifest.
+type ManifestEntry struct {
+	// Name is the name of the file in the archive.
+	Name string `header:"File" no:"1"`
+	// Rows is the number of data rows of the file.
+	Rows int `header:"Rows" no:"2"`
+	// SHA256 is the hexadecimal SHA-256 checksum of the file.
+	SHA256 string `header:"SHA256" no:"3"`
+}
+
+// NewZipWriter returns a ZipWriter writing to w. The options configure ConvertWithOptions for every file,
+// ConvertOptions.Gzip being ignored.
+//
+//	zw := csvx.NewZipWriter(w, csvx.ExcelDialect().Convert)
+//	zw.Manifest = "manifest.csv"
+//	err := csvx.AddCSV(zw, "orders.csv", orders)
+//	err = csvx.AddCSV(zw, "refunds.csv", refunds)
+//	err = zw.Close()
+func NewZipWriter(w io.Writer, options ...func(o *ConvertOptions)) *ZipWriter {
+	return &ZipWriter{zw: zip.NewWriter(w), options: options}
+}
+
+// AddCSV converts the structs with ConvertWithOptions and writes them to the archive as the file name.
+// An empty slice is written as the header row alone.
+func AddCSV[T any](zw *ZipWriter, name string, data []T) error {
+	o := convertOptions(zw.options)
+	o.Gzip = false
+	if err := o.Mapping.validate(columnsOf(reflect.TypeOf(data).Elem())); err != nil {
+		return err
+	}
+	csv := ""
+	if len(data) > 0 || !o.Headerless {
+		var err error
+		if csv, err = convertRows(data, o); err != nil {
+			return err
+		}
+	}
+	return zw.add(name, csv, len(data))
+}
+
+func (z *ZipWriter) add(name string, csv string, rows int) error {
+	f, err := z.zw.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, csv); err != nil {
+		return err
+	}
+	sum := sha256.Sum256([]byte(csv))
+	z.entries = append(z.entries, ManifestEntry{Name: name, Rows: rows, SHA256: hex.EncodeToString(sum[:])})
+	return nil
+}
+
+// Entries returns the files written so far, as listed in the manifest.
+func (z *ZipWriter) Entries() []ManifestEntry {
+	return append([]ManifestEntry(nil), z.entries...)
+}
+
+// Close writes the manifest and finishes the archive. It does not close the underlying io.Writer.
+// The manifest is written with the default ConvertOptions, whatever the options of the files.
+func (z *ZipWriter) Close() (err error) {
+	defer func() {
+		if cErr := z.zw.Close(); err == nil {
+			err = cErr
+		}
+	}()
+	if z.Manifest == "" {
+		return nil
+	}
+	csv, err := convertRows(z.entries, &ConvertOptions{})
+	if err != nil {
+		return err
+	}
+	entries := z.entries
+	err = z.add(z.Manifest, csv, len(entries))
+	z.entries = entries
+	return err
+}
@@ -0,0 +1,102 @@
+package csvx_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/prongbang/csvx"
+)
+
+func TestZipWriter(t *testing.T) {
+	// Given
+	var buffer bytes.Buffer
+	orders := []MyStruct{{ID: 1, Name: "N1"}, {ID: 2, Name: "N2"}}
+	refunds := []MyStruct{{ID: 3, Name: "=1+1"}}
+
+	// When
+	zw := csvx.NewZipWriter(&buffer, func(o *csvx.ConvertOptions) {
+		o.IgnoreDoubleQuote = true
+		o.OmitBOM = true
+	})
+	zw.Manifest = "manifest.csv"
+	err1 := csvx.AddCSV(zw, "orders.csv", orders)
+	err2 := csvx.AddCSV(zw, "refunds.csv", refunds)
+	err3 := zw.Close()
+
+	// Then
+	if err1 != nil || err2 != nil || err3 != nil {
+		t.Fatal("ZipWriter error", err1, err2, err3)
+	}
+	files := map[string][][]string{}
+	_ = csvx.ZipReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()), func(name string, rows [][]string) error {
+		files[name] = rows
+		return nil
+	})
+	if len(files) != 3 || files["refunds.csv"][1][1] != "'=1+1" {
+		t.Fatal("ZipWriter files", files)
+	}
+	sum := sha256.Sum256([]byte("ID,Name Space\n1,N1\n2,N2"))
+	manifest := files["manifest.csv"]
+	if manifest[1][0] != "orders.csv" || manifest[1][1] != "2" || manifest[1][2] != hex.EncodeToString(sum[:]) || manifest[2][1] != "1" {
+		t.Error("ZipWriter manifest", manifest)
+	}
+}
+
+func TestZipWriterManifestOptions(t *testing.T) {
+	// Given
+	var buffer bytes.Buffer
+	orders := []MyStruct{{ID: 1, Name: "N1"}}
+
+	// When
+	zw := csvx.NewZipWriter(&buffer, func(o *csvx.ConvertOptions) {
+		o.Mapping = csvx.Mapping{"Customer": "Name"}
+		o.Headerless = true
+		o.Comma = ';'
+	})
+	zw.Manifest = "manifest.csv"
+	err1 := csvx.AddCSV(zw, "orders.csv", orders)
+	err2 := zw.Close()
+
+	// Then
+	if err1 != nil || err2 != nil {
+		t.Fatal("ZipWriter error", err1, err2)
+	}
+	files := map[string][][]string{}
+	err := csvx.ZipReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()), func(name string, rows [][]string) error {
+		files[name] = rows
+		return nil
+	})
+	manifest := files["manifest.csv"]
+	if err != nil || len(manifest) != 2 || manifest[0][0] != "File" || manifest[1][0] != "orders.csv" || manifest[1][1] != "1" {
+		t.Error("ZipWriter manifest must keep its own options", manifest, err)
+	}
+}
+
+func TestZipWriterEmptyFile(t *testing.T) {
+	// Given
+	var buffer bytes.Buffer
+
+	// When
+	zw := csvx.NewZipWriter(&buffer)
+	zw.Manifest = "manifest.csv"
+	err1 := csvx.AddCSV(zw, "orders.csv", []MyStruct{})
+	err2 := zw.Close()
+
+	// Then
+	if err1 != nil || err2 != nil {
+		t.Fatal("ZipWriter error", err1, err2)
+	}
+	files := map[string][][]string{}
+	_ = csvx.ZipReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()), func(name string, rows [][]string) error {
+		files[name] = rows
+		return nil
+	})
+	if orders := files["orders.csv"]; len(orders) != 1 || orders[0][0] != "ID" {
+		t.Error("Empty file must have a header row", orders)
+	}
+	if manifest := files["manifest.csv"]; len(manifest) != 2 || manifest[1][1] != "0" {
+		t.Error("ZipWriter manifest", manifest)
+	}
+}